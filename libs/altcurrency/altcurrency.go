@@ -30,20 +30,28 @@ const (
 	ETH
 	// LTC Litecoin
 	LTC
+	// SOL Solana, whose subunit is the lamport
+	SOL
+	// SPLBAT Basic Attention Token bridged to Solana as an SPL token
+	SPLBAT
 )
 
 var altCurrencyName = map[AltCurrency]string{
-	BAT: "BAT",
-	BTC: "BTC",
-	ETH: "ETH",
-	LTC: "LTC",
+	BAT:    "BAT",
+	BTC:    "BTC",
+	ETH:    "ETH",
+	LTC:    "LTC",
+	SOL:    "SOL",
+	SPLBAT: "SPL-BAT",
 }
 
 var altCurrencyID = map[string]AltCurrency{
-	"BAT": BAT,
-	"BTC": BTC,
-	"ETH": ETH,
-	"LTC": LTC,
+	"BAT":     BAT,
+	"BTC":     BTC,
+	"ETH":     ETH,
+	"LTC":     LTC,
+	"SOL":     SOL,
+	"SPL-BAT": SPLBAT,
 }
 
 var altCurrencyDecimals = map[AltCurrency]int32{
@@ -51,6 +59,9 @@ var altCurrencyDecimals = map[AltCurrency]int32{
 	BTC: 8,
 	ETH: 18,
 	LTC: 8,
+	SOL: 9,
+	// the bridged token has fewer decimals than BAT, amounts finer than 10^-8 BAT can not be sent
+	SPLBAT: 8,
 }
 
 // IsValid returns true if a is a valid AltCurrency.
@@ -108,6 +108,41 @@ func TestToProbi(t *testing.T) {
 	}
 }
 
+func TestSolanaProbi(t *testing.T) {
+	f, err := decimal.NewFromString("1.23456789")
+	if err != nil {
+		t.Error(err)
+	}
+
+	lamports := SOL.ToProbi(f)
+	if !lamports.Equals(decimal.New(1234567890, 0)) {
+		t.Error("Expected lamport value to use 9 decimals", lamports)
+	}
+	if !SOL.FromProbi(lamports).Equals(f) {
+		t.Error("Expected lamport value to round trip to SOL value")
+	}
+
+	splBAT := SPLBAT.ToProbi(f)
+	if !splBAT.Equals(decimal.New(123456789, 0)) {
+		t.Error("Expected SPL-BAT value to use 8 decimals", splBAT)
+	}
+	if !SPLBAT.FromProbi(splBAT).Equals(f) {
+		t.Error("Expected SPL-BAT subunits to round trip to SPL-BAT value")
+	}
+	if splBAT.Equals(BAT.ToProbi(f)) {
+		t.Error("Expected SPL-BAT subunits to differ from BAT probi")
+	}
+
+	var a AltCurrency
+	if err := json.Unmarshal([]byte("\"SPL-BAT\""), &a); err != nil || a != SPLBAT {
+		t.Error("Expected SPL-BAT to unmarshal", err)
+	}
+	b, err := json.Marshal(&a)
+	if err != nil || string(b) != "\"SPL-BAT\"" {
+		t.Error("Expected SPL-BAT to marshal", err)
+	}
+}
+
 func TestToChecksumETHAddress(t *testing.T) {
 	addr := ToChecksumETHAddress("0xf1a61415e12db93abace8704855a4795934ff992")
 	if addr != "0xF1A61415e12DB93ABACE8704855A4795934ff992" {
@@ -0,0 +1,146 @@
+package altcurrency
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrUnitMismatch is returned when arithmetic mixes probi and nominal amounts
+	ErrUnitMismatch = errors.New("money units do not match")
+	// ErrCurrencyMismatch is returned when arithmetic mixes amounts of different currencies
+	ErrCurrencyMismatch = errors.New("money currencies do not match")
+)
+
+// Unit is the denomination an amount of Money is expressed in
+type Unit int
+
+const (
+	invalidUnit Unit = iota
+	// Nominal amounts are denominated in base units, e.g. 1.5 BAT
+	Nominal
+	// Probi amounts are denominated in sub units, e.g. 1500000000000000000 probi
+	Probi
+)
+
+func (u Unit) String() string {
+	switch u {
+	case Nominal:
+		return "nominal"
+	case Probi:
+		return "probi"
+	}
+	return "invalid"
+}
+
+// Money is an amount of an AltCurrency which keeps track of the unit it is denominated in
+type Money struct {
+	amount   decimal.Decimal
+	unit     Unit
+	currency AltCurrency
+}
+
+// NewNominal creates Money from amount, denominated in base units of currency
+func NewNominal(currency AltCurrency, amount decimal.Decimal) Money {
+	return Money{amount: amount, unit: Nominal, currency: currency}
+}
+
+// NewProbi creates Money from amount, denominated in sub units of currency
+func NewProbi(currency AltCurrency, amount decimal.Decimal) Money {
+	return Money{amount: amount, unit: Probi, currency: currency}
+}
+
+// Currency returns the currency of m
+func (m Money) Currency() AltCurrency {
+	return m.currency
+}
+
+// Unit returns the unit m is denominated in
+func (m Money) Unit() Unit {
+	return m.unit
+}
+
+// Amount returns the raw amount of m, in whichever unit it is denominated in
+func (m Money) Amount() decimal.Decimal {
+	return m.amount
+}
+
+// Probi returns the amount of m denominated in sub units
+func (m Money) Probi() decimal.Decimal {
+	if m.unit == Nominal {
+		return m.currency.ToProbi(m.amount)
+	}
+	return m.amount
+}
+
+// Nominal returns the amount of m denominated in base units
+func (m Money) Nominal() decimal.Decimal {
+	if m.unit == Probi {
+		return m.currency.FromProbi(m.amount)
+	}
+	return m.amount
+}
+
+// ToProbi returns m converted to sub units
+func (m Money) ToProbi() Money {
+	return NewProbi(m.currency, m.Probi())
+}
+
+// ToNominal returns m converted to base units
+func (m Money) ToNominal() Money {
+	return NewNominal(m.currency, m.Nominal())
+}
+
+func (m Money) compatible(o Money) error {
+	if m.currency != o.currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, o.currency)
+	}
+	if m.unit != o.unit {
+		return fmt.Errorf("%w: %s and %s", ErrUnitMismatch, m.unit, o.unit)
+	}
+	return nil
+}
+
+// Add returns m + o, m and o must share a currency and unit
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.compatible(o); err != nil {
+		return Money{}, err
+	}
+	return Money{amount: m.amount.Add(o.amount), unit: m.unit, currency: m.currency}, nil
+}
+
+// Sub returns m - o, m and o must share a currency and unit
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.compatible(o); err != nil {
+		return Money{}, err
+	}
+	return Money{amount: m.amount.Sub(o.amount), unit: m.unit, currency: m.currency}, nil
+}
+
+// Cmp compares m and o, returning -1, 0 or 1 as m is less than, equal to or greater than o
+// m and o must share a currency and unit
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.compatible(o); err != nil {
+		return 0, err
+	}
+	return m.amount.Cmp(o.amount), nil
+}
+
+// IsZero returns true if m is zero
+func (m Money) IsZero() bool {
+	return m.amount.IsZero()
+}
+
+// IsNegative returns true if m is less than zero
+func (m Money) IsNegative() bool {
+	return m.amount.IsNegative()
+}
+
+func (m Money) String() string {
+	if m.unit == Probi {
+		return fmt.Sprintf("%s probi %s", m.amount, m.currency)
+	}
+	return fmt.Sprintf("%s %s", m.amount, m.currency)
+}
@@ -0,0 +1,80 @@
+package altcurrency
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMoneyConversions(t *testing.T) {
+	nominal := NewNominal(BAT, decimal.NewFromFloat(1.5))
+	probi := nominal.ToProbi()
+
+	if probi.Unit() != Probi {
+		t.Error("Expected converted money to be denominated in probi")
+	}
+	if !probi.Amount().Equal(decimal.New(15, 17)) {
+		t.Error("Unexpected probi amount", probi.Amount())
+	}
+	if !probi.Nominal().Equal(nominal.Amount()) {
+		t.Error("Expected round trip to preserve the nominal amount")
+	}
+	if !nominal.Probi().Equal(probi.Amount()) {
+		t.Error("Expected nominal money to convert to probi")
+	}
+	if probi.String() != "1500000000000000000 probi BAT" {
+		t.Error("Unexpected string", probi.String())
+	}
+	if nominal.String() != "1.5 BAT" {
+		t.Error("Unexpected string", nominal.String())
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a := NewNominal(BAT, decimal.NewFromFloat(1.5))
+	b := NewNominal(BAT, decimal.NewFromFloat(0.5))
+
+	sum, err := a.Add(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sum.Amount().Equal(decimal.NewFromInt(2)) || sum.Unit() != Nominal {
+		t.Error("Unexpected sum", sum)
+	}
+
+	diff, err := b.Sub(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.IsNegative() {
+		t.Error("Expected negative difference", diff)
+	}
+
+	cmp, err := a.Cmp(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmp != 1 {
+		t.Error("Expected a to be greater than b")
+	}
+}
+
+func TestMoneyRefusesToMix(t *testing.T) {
+	nominal := NewNominal(BAT, decimal.NewFromInt(1))
+
+	if _, err := nominal.Add(NewProbi(BAT, decimal.NewFromInt(1))); !errors.Is(err, ErrUnitMismatch) {
+		t.Error("Expected unit mismatch error, got", err)
+	}
+	if _, err := nominal.Cmp(NewProbi(BAT, decimal.NewFromInt(1))); !errors.Is(err, ErrUnitMismatch) {
+		t.Error("Expected unit mismatch error, got", err)
+	}
+	if _, err := nominal.Sub(NewNominal(ETH, decimal.NewFromInt(1))); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Error("Expected currency mismatch error, got", err)
+	}
+
+	// explicit conversion makes the units compatible
+	if _, err := nominal.ToProbi().Add(NewProbi(BAT, decimal.NewFromInt(1))); err != nil {
+		t.Error("Unexpected error", err)
+	}
+}
@@ -0,0 +1,195 @@
+// Package ratesclient provides a common interface for exchange rate sources so that tools
+// apply the same staleness and caching rules regardless of where a rate comes from
+package ratesclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brave-intl/bat-go/libs/clients/ratios"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrRateNotFound is returned when the source has no rate for the currency pair
+	ErrRateNotFound = errors.New("rate not found")
+	// ErrStaleRate is returned when the rate is older than the maximum age allowed
+	ErrStaleRate = errors.New("rate is too old")
+)
+
+// Rate is the value of one unit of a currency in another currency
+type Rate struct {
+	Value     decimal.Decimal
+	Timestamp time.Time
+	Source    string
+}
+
+// Client fetches exchange rates
+type Client interface {
+	// GetRate returns the value of one unit of from in to
+	GetRate(ctx context.Context, from string, to string) (Rate, error)
+}
+
+// ratiosClient adapts the ratios service to Client
+type ratiosClient struct {
+	client ratios.Client
+}
+
+// NewRatios returns a Client backed by the ratios service
+func NewRatios(client ratios.Client) Client {
+	return &ratiosClient{client: client}
+}
+
+// GetRate fetches the rate from the ratios service
+func (c *ratiosClient) GetRate(ctx context.Context, from string, to string) (Rate, error) {
+	resp, err := c.client.FetchRate(ctx, from, to)
+	if err != nil {
+		return Rate{}, err
+	}
+	if resp == nil {
+		return Rate{}, ErrRateNotFound
+	}
+
+	// ratios keys the payload by the lowercase currency
+	value, ok := resp.Payload[strings.ToLower(to)]
+	if !ok {
+		value, ok = resp.Payload[to]
+	}
+	if !ok {
+		return Rate{}, ErrRateNotFound
+	}
+	return Rate{
+		Value:     value,
+		Timestamp: resp.LastUpdated,
+		Source:    "ratios",
+	}, nil
+}
+
+// maxAgeClient rejects rates older than maxAge
+type maxAgeClient struct {
+	client Client
+	maxAge time.Duration
+}
+
+// WithMaxAge returns a Client which returns ErrStaleRate for rates older than maxAge
+func WithMaxAge(client Client, maxAge time.Duration) Client {
+	return &maxAgeClient{client: client, maxAge: maxAge}
+}
+
+// GetRate fetches the rate and checks its age
+func (c *maxAgeClient) GetRate(ctx context.Context, from string, to string) (Rate, error) {
+	rate, err := c.client.GetRate(ctx, from, to)
+	if err != nil {
+		return rate, err
+	}
+	if age := time.Since(rate.Timestamp); age > c.maxAge {
+		return rate, fmt.Errorf("%s/%s rate from %s is %s old: %w", from, to, rate.Source, age.Round(time.Second), ErrStaleRate)
+	}
+	return rate, nil
+}
+
+// revalidateTimeout bounds a background refresh of a stale rate
+const revalidateTimeout = 30 * time.Second
+
+// CacheOption configures a Client returned by WithCache
+type CacheOption func(*cachedClient)
+
+// StaleWhileRevalidate allows a rate to be returned for up to window after it expires while it is
+// refreshed in the background, so that a slow source does not block callers that accept a slightly
+// stale rate. Staleness checks wrapping the cache still see the timestamp of the source
+func StaleWhileRevalidate(window time.Duration) CacheOption {
+	return func(c *cachedClient) {
+		c.stale = window
+	}
+}
+
+type cacheEntry struct {
+	rate       Rate
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+// cachedClient caches rates by currency pair
+type cachedClient struct {
+	client Client
+	ttl    time.Duration
+	stale  time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+// WithCache returns a Client which reuses rates fetched within the last ttl
+// cached rates keep the timestamp of the source, so staleness checks wrapping the cache still apply
+func WithCache(client Client, ttl time.Duration, opts ...CacheOption) Client {
+	c := &cachedClient{
+		client:  client,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]*cacheEntry{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// GetRate returns the cached rate for the pair, fetching it if not present or expired
+func (c *cachedClient) GetRate(ctx context.Context, from string, to string) (Rate, error) {
+	key := strings.ToLower(from) + "_" + strings.ToLower(to)
+
+	c.mu.Lock()
+	entry, found := c.entries[key]
+	if found {
+		age := c.now().Sub(entry.fetchedAt)
+		if age < c.ttl {
+			c.mu.Unlock()
+			return entry.rate, nil
+		}
+		if age < c.ttl+c.stale {
+			if !entry.refreshing {
+				entry.refreshing = true
+				go c.revalidate(key, from, to)
+			}
+			c.mu.Unlock()
+			return entry.rate, nil
+		}
+	}
+	c.mu.Unlock()
+
+	rate, err := c.client.GetRate(ctx, from, to)
+	if err != nil {
+		return rate, err
+	}
+	c.store(key, rate)
+	return rate, nil
+}
+
+// revalidate refreshes a stale rate, the stale rate is kept if the refresh fails
+func (c *cachedClient) revalidate(key, from, to string) {
+	// the caller may return before the refresh completes, so it must not share the caller context
+	ctx, cancel := context.WithTimeout(context.Background(), revalidateTimeout)
+	defer cancel()
+
+	rate, err := c.client.GetRate(ctx, from, to)
+	if err != nil {
+		c.mu.Lock()
+		if entry, ok := c.entries[key]; ok {
+			entry.refreshing = false
+		}
+		c.mu.Unlock()
+		return
+	}
+	c.store(key, rate)
+}
+
+func (c *cachedClient) store(key string, rate Rate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = &cacheEntry{rate: rate, fetchedAt: c.now()}
+}
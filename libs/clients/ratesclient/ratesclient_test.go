@@ -0,0 +1,169 @@
+package ratesclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brave-intl/bat-go/libs/clients/ratios"
+	mockratios "github.com/brave-intl/bat-go/libs/clients/ratios/mock"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingClient struct {
+	mu    sync.Mutex
+	calls int
+	rate  Rate
+	// block, when set, holds each fetch until it is closed
+	block chan struct{}
+}
+
+func (c *countingClient) GetRate(ctx context.Context, from string, to string) (Rate, error) {
+	if c.block != nil {
+		<-c.block
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	return c.rate, nil
+}
+
+func (c *countingClient) Calls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func newTestCache(source Client, clock *fakeClock, ttl time.Duration, opts ...CacheOption) Client {
+	c := WithCache(source, ttl, opts...).(*cachedClient)
+	c.now = clock.Now
+	return c
+}
+
+func TestRatiosGetRate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	updated := time.Now().Add(-time.Minute)
+
+	mockRatios := mockratios.NewMockClient(ctrl)
+	mockRatios.EXPECT().FetchRate(gomock.Any(), "BAT", "JPY").Return(&ratios.RateResponse{
+		LastUpdated: updated,
+		Payload:     map[string]decimal.Decimal{"jpy": decimal.RequireFromString("31.5")},
+	}, nil)
+	mockRatios.EXPECT().FetchRate(gomock.Any(), "BAT", "XYZ").Return(&ratios.RateResponse{
+		LastUpdated: updated,
+		Payload:     map[string]decimal.Decimal{},
+	}, nil)
+
+	client := NewRatios(mockRatios)
+
+	rate, err := client.GetRate(ctx, "BAT", "JPY")
+	assert.NoError(t, err)
+	assert.True(t, rate.Value.Equal(decimal.RequireFromString("31.5")))
+	assert.Equal(t, updated, rate.Timestamp)
+	assert.Equal(t, "ratios", rate.Source)
+
+	_, err = client.GetRate(ctx, "BAT", "XYZ")
+	assert.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func TestWithMaxAge(t *testing.T) {
+	ctx := context.Background()
+
+	fresh := &countingClient{rate: Rate{Value: decimal.NewFromInt(1), Timestamp: time.Now()}}
+	_, err := WithMaxAge(fresh, 5*time.Minute).GetRate(ctx, "BAT", "USD")
+	assert.NoError(t, err)
+
+	stale := &countingClient{rate: Rate{Value: decimal.NewFromInt(1), Timestamp: time.Now().Add(-10 * time.Minute)}}
+	_, err = WithMaxAge(stale, 5*time.Minute).GetRate(ctx, "BAT", "USD")
+	assert.True(t, errors.Is(err, ErrStaleRate))
+}
+
+func TestWithCache(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Now()}
+
+	source := &countingClient{rate: Rate{Value: decimal.NewFromInt(1), Timestamp: time.Now()}}
+	client := newTestCache(source, clock, time.Minute)
+
+	_, err := client.GetRate(ctx, "BAT", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, source.Calls(), "a miss should fetch the rate")
+
+	_, err = client.GetRate(ctx, "bat", "usd")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, source.Calls(), "the pair should be cached regardless of case")
+
+	_, err = client.GetRate(ctx, "BAT", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, source.Calls(), "each pair should be cached separately")
+
+	clock.Advance(time.Minute)
+	_, err = client.GetRate(ctx, "BAT", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, source.Calls(), "the rate should be fetched again once expired")
+}
+
+func TestWithCacheStaleWhileRevalidate(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Now()}
+
+	source := &countingClient{rate: Rate{Value: decimal.NewFromInt(1), Timestamp: time.Now()}}
+	client := newTestCache(source, clock, time.Minute, StaleWhileRevalidate(time.Minute))
+
+	_, err := client.GetRate(ctx, "BAT", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, source.Calls())
+
+	// a slow source must not block a stale read
+	source.rate = Rate{Value: decimal.NewFromInt(2), Timestamp: time.Now()}
+	source.block = make(chan struct{})
+	clock.Advance(90 * time.Second)
+
+	rate, err := client.GetRate(ctx, "BAT", "USD")
+	assert.NoError(t, err)
+	assert.True(t, rate.Value.Equal(decimal.NewFromInt(1)), "the stale rate should be returned")
+
+	// only one refresh is started while one is in flight
+	rate, err = client.GetRate(ctx, "BAT", "USD")
+	assert.NoError(t, err)
+	assert.True(t, rate.Value.Equal(decimal.NewFromInt(1)))
+
+	close(source.block)
+	assert.Eventually(t, func() bool {
+		rate, err := client.GetRate(ctx, "BAT", "USD")
+		return err == nil && rate.Value.Equal(decimal.NewFromInt(2))
+	}, time.Second, 10*time.Millisecond, "the refreshed rate should replace the stale one")
+	assert.Equal(t, 2, source.Calls())
+
+	// past the stale window the rate is fetched before returning
+	source.block = nil
+	source.rate = Rate{Value: decimal.NewFromInt(3), Timestamp: time.Now()}
+	clock.Advance(3 * time.Minute)
+	rate, err = client.GetRate(ctx, "BAT", "USD")
+	assert.NoError(t, err)
+	assert.True(t, rate.Value.Equal(decimal.NewFromInt(3)))
+}
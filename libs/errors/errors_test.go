@@ -82,3 +82,25 @@ func TestErrorBundle_DataToString(t *testing.T) {
 
 	assert.Equal(t, string(expected), actual.DataToString())
 }
+
+type transientErr struct {
+	transient bool
+}
+
+func (te transientErr) Error() string {
+	return "transient error"
+}
+
+func (te transientErr) Transient() bool {
+	return te.transient
+}
+
+func TestIsErrTransient(t *testing.T) {
+	assert.True(t, errutil.IsErrTransient(transientErr{transient: true}))
+	assert.True(t, errutil.IsErrTransient(errutil.New(transientErr{transient: true}, "failed to submit", nil)))
+	assert.True(t, errutil.IsErrTransient(fmt.Errorf("%w: timeout", errutil.ErrFailedClientRequest)))
+
+	assert.False(t, errutil.IsErrTransient(transientErr{transient: false}))
+	assert.False(t, errutil.IsErrTransient(errors.New(testutils.RandomString())))
+	assert.False(t, errutil.IsErrTransient(nil))
+}
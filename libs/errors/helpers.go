@@ -1,5 +1,7 @@
 package errors
 
+import "errors"
+
 // IsErrNotFound is a helper method for determining if an error indicates a missing resource
 func IsErrNotFound(err error) bool {
 	type notFound interface {
@@ -62,3 +64,16 @@ func IsErrForbidden(err error) bool {
 	te, ok := err.(forbidden)
 	return ok && te.ForbiddenError()
 }
+
+// IsErrTransient is a helper method for determining if an error is temporary and the request can be retried,
+// such as a rate limit, a server error or a failure to reach the server
+func IsErrTransient(err error) bool {
+	type transient interface {
+		Transient() bool
+	}
+	var te transient
+	if errors.As(err, &te) && te.Transient() {
+		return true
+	}
+	return errors.Is(err, ErrFailedClientRequest)
+}
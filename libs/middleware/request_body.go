@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+)
+
+const (
+	// captureBodyEnv enables logging of request bodies for responses with an error status
+	captureBodyEnv = "REQUEST_LOGGER_CAPTURE_BODY"
+	// maxCapturedBody is the most bytes of a request body kept for logging
+	maxCapturedBody = 4096
+	redacted        = `"[REDACTED]"`
+)
+
+// sensitiveFieldRE matches json members whose name suggests a secret, capturing the name
+var sensitiveFieldRE = regexp.MustCompile(
+	`(?i)("[^"]*(?:password|secret|token|signature|private_?key|authorization|api_?key|credential|creds|recovery)[^"]*"\s*:\s*)` +
+		`("(?:[^"\\]|\\.)*"?|\[[^\]]*\]?|\{[^}]*\}?|[^,}\s]+)`)
+
+func captureBodyEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(captureBodyEnv))
+	return enabled
+}
+
+// capturingReadCloser keeps a copy of up to limit bytes read from the body
+// without consuming it, so the handler still sees the full request body
+type capturingReadCloser struct {
+	io.ReadCloser
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func newCapturingReadCloser(body io.ReadCloser, limit int) *capturingReadCloser {
+	return &capturingReadCloser{ReadCloser: body, limit: limit}
+}
+
+func (c *capturingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.capture(p[:n])
+	return n, err
+}
+
+func (c *capturingReadCloser) capture(p []byte) {
+	remaining := c.limit - c.buf.Len()
+	if len(p) > remaining {
+		c.truncated = true
+		p = p[:remaining]
+	}
+	c.buf.Write(p)
+}
+
+// drain captures whatever the handler left unread, up to the limit
+func (c *capturingReadCloser) drain() {
+	if c.truncated {
+		return
+	}
+	rest, _ := io.ReadAll(io.LimitReader(c.ReadCloser, int64(c.limit-c.buf.Len()+1)))
+	c.capture(rest)
+}
+
+// String returns the captured body with sensitive fields redacted
+func (c *capturingReadCloser) String() string {
+	body := redactBody(c.buf.Bytes())
+	if c.truncated {
+		body += "...(truncated)"
+	}
+	return body
+}
+
+// redactBody replaces the values of json members with sensitive sounding names
+func redactBody(body []byte) string {
+	return string(sensitiveFieldRE.ReplaceAll(body, []byte("${1}"+redacted)))
+}
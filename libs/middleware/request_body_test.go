@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactBody(t *testing.T) {
+	body := `{"paymentId":"abc","password": "hunter2","nested":{"apiKey":42,"private_key":"x"},"tokens":["a","b"],"kind":"grant"}`
+	actual := redactBody([]byte(body))
+
+	assert.Equal(t,
+		`{"paymentId":"abc","password": "[REDACTED]","nested":{"apiKey":"[REDACTED]","private_key":"[REDACTED]"},"tokens":"[REDACTED]","kind":"grant"}`,
+		actual)
+	assert.NotContains(t, actual, "hunter2")
+}
+
+func TestCapturingReadCloser(t *testing.T) {
+	body := strings.Repeat("a", 10)
+	c := newCapturingReadCloser(io.NopCloser(strings.NewReader(body)), 4)
+
+	// the handler still reads the whole body
+	read, err := io.ReadAll(c)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(read))
+
+	assert.Equal(t, "aaaa...(truncated)", c.String())
+}
+
+func TestCapturingReadCloser_DrainUnread(t *testing.T) {
+	c := newCapturingReadCloser(io.NopCloser(strings.NewReader(`{"secret":"s"}`)), maxCapturedBody)
+
+	c.drain()
+	assert.Equal(t, `{"secret":"[REDACTED]"}`, c.String())
+}
@@ -10,6 +10,10 @@ import (
 	"github.com/shengdoushi/base58"
 )
 
+// RequestIDResponseHeader is the response header hlog.RequestIDHandler echoes the logged req_id in,
+// clients can quote it so the server logs for their request can be found
+const RequestIDResponseHeader = "Request-Id"
+
 // RequestIDTransfer transfers the request id from header to context
 func RequestIDTransfer(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
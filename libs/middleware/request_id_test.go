@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/hlog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDResponseHeader(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := zerolog.New(buffer)
+
+	router := chi.NewRouter()
+	router.Use(hlog.NewHandler(logger))
+	router.Use(hlog.RequestIDHandler("req_id", RequestIDResponseHeader))
+	router.Use(RequestLogger(&logger))
+	router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	reqID := rw.Header().Get(RequestIDResponseHeader)
+	require.NotEmpty(t, reqID)
+
+	// every log line for the request carries the id returned to the client
+	lines := bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n"))
+	require.NotEmpty(t, lines)
+	for _, line := range lines {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &entry))
+		assert.Equal(t, reqID, entry["req_id"])
+	}
+}
@@ -49,7 +49,10 @@ var ipPortRE = regexp.MustCompile(`[0-9]+(?:\.[0-9]+){3}(:[0-9]+)?`)
 // RequestLogger logs at the start and stop of incoming HTTP requests as well as recovers from panics
 // Modified version of RequestLogger from github.com/rs/zerolog
 // Added support for sending captured panic to Sentry
+// When REQUEST_LOGGER_CAPTURE_BODY is set a size capped, redacted copy of the request body is logged
+// for responses with an error status, this buffers part of every request body so is off by default
 func RequestLogger(logger *zerolog.Logger) func(next http.Handler) http.Handler {
+	captureBody := captureBodyEnabled()
 	return func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
 			if r.URL.EscapedPath() == "/metrics" { // Skip logging prometheus metric scrapes
@@ -63,6 +66,12 @@ func RequestLogger(logger *zerolog.Logger) func(next http.Handler) http.Handler
 			logger := hlog.FromRequest(r)
 			createSubLog(logger, r, 0).Msg("request started")
 
+			var body *capturingReadCloser
+			if captureBody && r.Body != nil && r.Body != http.NoBody {
+				body = newCapturingReadCloser(r.Body, maxCapturedBody)
+				r.Body = body
+			}
+
 			defer func() {
 				t2 := time.Now().UTC()
 
@@ -88,7 +97,12 @@ func RequestLogger(logger *zerolog.Logger) func(next http.Handler) http.Handler
 
 				status := ww.Status()
 				// Log the entry, the request is complete.
-				createSubLog(logger, r, status).Int("status", status).Int("size", ww.BytesWritten()).Dur("duration", t2.Sub(t1)).Msg("request complete")
+				entry := createSubLog(logger, r, status).Int("status", status).Int("size", ww.BytesWritten()).Dur("duration", t2.Sub(t1))
+				if body != nil && status >= 400 {
+					body.drain()
+					entry = entry.Str("request_body", body.String())
+				}
+				entry.Msg("request complete")
 			}()
 
 			r = r.WithContext(logger.WithContext(r.Context()))
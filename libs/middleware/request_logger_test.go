@@ -3,9 +3,11 @@ package middleware
 import (
 	"bytes"
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/go-chi/chi"
@@ -44,3 +46,33 @@ func TestRequestLogger_LogsPanic(t *testing.T) {
 	assert.Regexp(t, regexp.MustCompile("panic=.+panicky handler"), actual)
 	assert.Regexp(t, regexp.MustCompile("stacktrace=.+"), actual)
 }
+
+func TestRequestLogger_CapturesBodyOnError(t *testing.T) {
+	t.Setenv(captureBodyEnv, "true")
+
+	buffer := &bytes.Buffer{}
+	logger := zerolog.New(buffer)
+	ctx := logger.WithContext(context.Background())
+
+	var handled string
+	router := chi.NewRouter()
+	router.Use(RequestLogger(&logger))
+	router.Post("/", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		handled = string(b)
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	router.Post("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	body := `{"kind":"grant","signature":"sig"}`
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)).WithContext(ctx))
+
+	assert.Equal(t, body, handled)
+	assert.Contains(t, buffer.String(), `"request_body":"{\"kind\":\"grant\",\"signature\":\"[REDACTED]\"}"`)
+
+	buffer.Reset()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/ok", strings.NewReader(body)).WithContext(ctx))
+	assert.NotContains(t, buffer.String(), "request_body")
+}
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 )
 
 // ContextDialer is a function connecting to the address on the named network
@@ -29,16 +30,22 @@ func validateChain(fingerprint string, connstate tls.ConnectionState) error {
 
 // MakeContextDialer returns a ContextDialer that only succeeds on connection to a TLS secured address with the pinned fingerprint
 func MakeContextDialer(fingerprint string) ContextDialer {
+	return makeContextDialer(fingerprint, nil)
+}
+
+func makeContextDialer(fingerprint string, config *tls.Config) ContextDialer {
 	return func(ctx context.Context, network, addr string) (net.Conn, error) {
-		c, err := tls.Dial(network, addr, nil)
+		c, err := tls.Dial(network, addr, config)
 		if err != nil {
 			return c, err
 		}
 		select {
 		case <-ctx.Done():
+			_ = c.Close()
 			return nil, fmt.Errorf("context completed")
 		default:
 			if err := validateChain(fingerprint, c.ConnectionState()); err != nil {
+				_ = c.Close()
 				return nil, fmt.Errorf("failed to validate certificate chain: %w", err)
 			}
 		}
@@ -46,6 +53,16 @@ func MakeContextDialer(fingerprint string) ContextDialer {
 	}
 }
 
+// NewPinnedTransport returns an http transport which refuses TLS connections to servers whose verified
+// certificate chain does not include the pinned fingerprint
+// HTTP/2 is explicitly disabled, it is not used with a custom TLS dialer
+func NewPinnedTransport(fingerprint string) *http.Transport {
+	return &http.Transport{
+		DialTLSContext: MakeContextDialer(fingerprint),
+		TLSNextProto:   make(map[string]func(authority string, c *tls.Conn) http.RoundTripper),
+	}
+}
+
 // GetFingerprints is a helper for getting the fingerprint needed to update pins
 func GetFingerprints(c *tls.Conn) (map[string]string, error) {
 	connstate := c.ConnectionState()
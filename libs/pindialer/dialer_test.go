@@ -0,0 +1,42 @@
+package pindialer
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMakeContextDialer(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+	config := &tls.Config{RootCAs: roots}
+
+	hash := sha256.Sum256(srv.Certificate().RawSubjectPublicKeyInfo)
+	fingerprint := base64.StdEncoding.EncodeToString(hash[:])
+	addr := strings.TrimPrefix(srv.URL, "https://")
+
+	c, err := makeContextDialer(fingerprint, config)(context.Background(), "tcp", addr)
+	if err != nil {
+		t.Fatalf("expected connection with the correct pin to succeed: %v", err)
+	}
+	_ = c.Close()
+
+	wrongFingerprint := "IYSLsapSKlkofKfi6M2hmS4gzXbQKGIX/DHBWIgstw3="
+	c, err = makeContextDialer(wrongFingerprint, config)(context.Background(), "tcp", addr)
+	if err == nil {
+		_ = c.Close()
+		t.Fatal("expected connection with the wrong pin to fail")
+	}
+	if err.Error() != "failed to validate certificate chain: the server certificate was not valid" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package sentryutil
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appctx "github.com/brave-intl/bat-go/libs/context"
+	"github.com/getsentry/sentry-go"
+)
+
+const flushTimeout = 2 * time.Second
+
+// Options - the attributes attached to every event reported to sentry
+type Options struct {
+	DSN         string
+	Service     string
+	Environment string
+	Version     string
+	Commit      string
+	BuildTime   string
+}
+
+// OptionsFromContext - build options for service from the build and environment details held in ctx
+func OptionsFromContext(ctx context.Context, dsn, service string) Options {
+	env, _ := ctx.Value(appctx.EnvironmentCTXKey).(string)
+	version, _ := ctx.Value(appctx.VersionCTXKey).(string)
+	commit, _ := ctx.Value(appctx.CommitCTXKey).(string)
+	buildTime, _ := ctx.Value(appctx.BuildTimeCTXKey).(string)
+	return Options{
+		DSN:         dsn,
+		Service:     service,
+		Environment: env,
+		Version:     version,
+		Commit:      commit,
+		BuildTime:   buildTime,
+	}
+}
+
+// Release - the sentry release name for the options
+func (o Options) Release() string {
+	return fmt.Sprintf("bat-go@%s-%s", o.Commit, o.BuildTime)
+}
+
+// Init - initialize sentry with a release, environment and service tags set uniformly
+// the returned func flushes buffered events and should be deferred by the caller,
+// when no dsn is configured sentry is left disabled and the flush func does nothing
+func Init(opts Options) (func(), error) {
+	if opts.DSN == "" {
+		return func() {}, nil
+	}
+
+	err := sentry.Init(sentry.ClientOptions{
+		Dsn:         opts.DSN,
+		Release:     opts.Release(),
+		Environment: opts.Environment,
+	})
+	if err != nil {
+		return func() {}, fmt.Errorf("failed to initialize sentry: %w", err)
+	}
+
+	sentry.ConfigureScope(func(scope *sentry.Scope) {
+		scope.SetTags(map[string]string{
+			"service":     opts.Service,
+			"environment": opts.Environment,
+			"version":     opts.Version,
+			"commit":      opts.Commit,
+		})
+	})
+
+	return func() {
+		sentry.Flush(flushTimeout)
+	}, nil
+}
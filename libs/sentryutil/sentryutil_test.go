@@ -0,0 +1,37 @@
+package sentryutil
+
+import (
+	"context"
+	"testing"
+
+	appctx "github.com/brave-intl/bat-go/libs/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionsFromContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), appctx.EnvironmentCTXKey, "staging")
+	ctx = context.WithValue(ctx, appctx.VersionCTXKey, "v1.2.3")
+	ctx = context.WithValue(ctx, appctx.CommitCTXKey, "abc123")
+	ctx = context.WithValue(ctx, appctx.BuildTimeCTXKey, "2023-01-01")
+
+	opts := OptionsFromContext(ctx, "dsn", "grant")
+	assert.Equal(t, Options{
+		DSN:         "dsn",
+		Service:     "grant",
+		Environment: "staging",
+		Version:     "v1.2.3",
+		Commit:      "abc123",
+		BuildTime:   "2023-01-01",
+	}, opts)
+	assert.Equal(t, "bat-go@abc123-2023-01-01", opts.Release())
+}
+
+func TestInit(t *testing.T) {
+	flush, err := Init(Options{Service: "grant"})
+	assert.NoError(t, err, "no dsn leaves sentry disabled")
+	assert.NotNil(t, flush)
+	flush()
+
+	_, err = Init(Options{DSN: "not a dsn", Service: "grant"})
+	assert.Error(t, err)
+}
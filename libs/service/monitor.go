@@ -0,0 +1,164 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// JobStatus - point in time view of a registered job's execution
+type JobStatus struct {
+	Name        string     `json:"name"`
+	Workers     int        `json:"workers"`
+	Cadence     string     `json:"cadence"`
+	Running     int        `json:"running"`
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+	LastError   string     `json:"lastError,omitempty"`
+	LastErrorAt *time.Time `json:"lastErrorAt,omitempty"`
+}
+
+// JobMonitor - tracks the execution state of job workers
+// a nil *JobMonitor is valid and records nothing
+type JobMonitor struct {
+	mu       sync.RWMutex
+	order    []string
+	jobs     map[string]*JobStatus
+	active   sync.WaitGroup
+	stopping bool
+	stop     chan struct{}
+}
+
+// NewJobMonitor - create a new job monitor
+func NewJobMonitor() *JobMonitor {
+	return &JobMonitor{
+		jobs: make(map[string]*JobStatus),
+		stop: make(chan struct{}),
+	}
+}
+
+// Register - register a job with the monitor, returning the name it is tracked under
+func (m *JobMonitor) Register(job Job) string {
+	if m == nil {
+		return job.Name
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name := job.Name
+	if name == "" {
+		name = fmt.Sprintf("job-%d", len(m.order))
+	}
+
+	if _, ok := m.jobs[name]; !ok {
+		m.order = append(m.order, name)
+	}
+	m.jobs[name] = &JobStatus{
+		Name:    name,
+		Workers: job.Workers,
+		Cadence: job.Cadence.String(),
+	}
+	return name
+}
+
+// start records the start of a job run, returning false if the monitor is draining and the run should not start
+func (m *JobMonitor) start(name string) bool {
+	if m == nil {
+		return true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopping {
+		return false
+	}
+	m.active.Add(1)
+	if s, ok := m.jobs[name]; ok {
+		s.Running++
+	}
+	return true
+}
+
+func (m *JobMonitor) finish(name string, err error) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.active.Done()
+	s, ok := m.jobs[name]
+	if !ok {
+		return
+	}
+
+	s.Running--
+	now := time.Now().UTC()
+	if err != nil {
+		s.LastError = err.Error()
+		s.LastErrorAt = &now
+		return
+	}
+	s.LastSuccess = &now
+}
+
+// Status - get the status of all registered jobs, in registration order
+func (m *JobMonitor) Status() []JobStatus {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]JobStatus, 0, len(m.order))
+	for _, name := range m.order {
+		result = append(result, *m.jobs[name])
+	}
+	return result
+}
+
+// stopped returns a channel which is closed once the monitor starts draining
+func (m *JobMonitor) stopped() <-chan struct{} {
+	if m == nil {
+		return nil
+	}
+	return m.stop
+}
+
+// Drain - stop workers from starting new job runs and wait up to timeout for in-flight runs to finish
+// the names of any jobs still running when the timeout elapses are returned
+func (m *JobMonitor) Drain(timeout time.Duration) []string {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	if !m.stopping {
+		m.stopping = true
+		close(m.stop)
+	}
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.active.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+	}
+
+	var running []string
+	for _, s := range m.Status() {
+		if s.Running > 0 {
+			running = append(running, s.Name)
+		}
+	}
+	return running
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobMonitor(t *testing.T) {
+	monitor := NewJobMonitor()
+
+	name := monitor.Register(Job{Name: "drain", Workers: 2, Cadence: time.Second})
+	assert.Equal(t, "drain", name)
+	unnamed := monitor.Register(Job{Workers: 1, Cadence: time.Minute})
+	assert.Equal(t, "job-1", unnamed)
+
+	monitor.start(name)
+	status := monitor.Status()
+	assert.Len(t, status, 2)
+	assert.Equal(t, 1, status[0].Running)
+	assert.Nil(t, status[0].LastSuccess)
+
+	monitor.finish(name, nil)
+	monitor.start(unnamed)
+	monitor.finish(unnamed, errors.New("boom"))
+
+	status = monitor.Status()
+	assert.Equal(t, 0, status[0].Running)
+	assert.NotNil(t, status[0].LastSuccess)
+	assert.Equal(t, "1s", status[0].Cadence)
+	assert.Equal(t, "boom", status[1].LastError)
+	assert.NotNil(t, status[1].LastErrorAt)
+	assert.Nil(t, status[1].LastSuccess)
+}
+
+func TestJobMonitorNil(t *testing.T) {
+	var monitor *JobMonitor
+
+	assert.Equal(t, "drain", monitor.Register(Job{Name: "drain"}))
+	monitor.start("drain")
+	monitor.finish("drain", nil)
+	assert.Nil(t, monitor.Status())
+}
+
+func TestJobMonitorDrain(t *testing.T) {
+	monitor := NewJobMonitor()
+	name := monitor.Register(Job{Name: "settle", Workers: 1, Cadence: time.Second})
+
+	assert.True(t, monitor.start(name))
+	// the in-flight run outlives the drain deadline
+	assert.Equal(t, []string{"settle"}, monitor.Drain(10*time.Millisecond))
+	// no new runs start once draining
+	assert.False(t, monitor.start(name))
+
+	go func() {
+		<-time.After(10 * time.Millisecond)
+		monitor.finish(name, nil)
+	}()
+	assert.Nil(t, monitor.Drain(5*time.Second))
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxJobWorkers  = 32
+	minJobCadence  = 10 * time.Millisecond
+	maxJobCadence  = 24 * time.Hour
+	jobEnvPrefix   = "JOB_"
+	workersEnvName = "_WORKERS"
+	cadenceEnvName = "_CADENCE"
+)
+
+// JobEnvName - the environment variable prefix used to override settings of the named job
+// e.g. a job named "vote-drain" is configured by JOB_VOTE_DRAIN_WORKERS and JOB_VOTE_DRAIN_CADENCE
+func JobEnvName(name string) string {
+	return jobEnvPrefix + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(name))
+}
+
+// ApplyJobOverrides - override the worker count and cadence of named jobs using lookup (usually os.LookupEnv)
+// setting workers to 0 disables a job, jobs without a name cannot be overridden
+func ApplyJobOverrides(jobs []Job, lookup func(string) (string, bool)) ([]Job, error) {
+	result := make([]Job, len(jobs))
+	for i, job := range jobs {
+		result[i] = job
+		if job.Name == "" {
+			continue
+		}
+		prefix := JobEnvName(job.Name)
+
+		if v, ok := lookup(prefix + workersEnvName); ok {
+			workers, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s%s: %w", prefix, workersEnvName, err)
+			}
+			if workers < 0 || workers > maxJobWorkers {
+				return nil, fmt.Errorf("invalid %s%s: %d is not between 0 and %d", prefix, workersEnvName, workers, maxJobWorkers)
+			}
+			result[i].Workers = workers
+		}
+
+		if v, ok := lookup(prefix + cadenceEnvName); ok {
+			cadence, err := time.ParseDuration(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s%s: %w", prefix, cadenceEnvName, err)
+			}
+			if cadence < minJobCadence || cadence > maxJobCadence {
+				return nil, fmt.Errorf("invalid %s%s: %s is not between %s and %s", prefix, cadenceEnvName, cadence, minJobCadence, maxJobCadence)
+			}
+			result[i].Cadence = cadence
+		}
+	}
+	return result, nil
+}
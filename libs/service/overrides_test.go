@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lookupFromMap(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestJobEnvName(t *testing.T) {
+	assert.Equal(t, "JOB_VOTE_DRAIN", JobEnvName("vote-drain"))
+	assert.Equal(t, "JOB_SEND_SIGNING_REQUEST", JobEnvName("send-signing-request"))
+}
+
+func TestApplyJobOverrides(t *testing.T) {
+	jobs := []Job{
+		{Name: "vote-drain", Workers: 1, Cadence: 2 * time.Second},
+		{Name: "send-signing-request", Workers: 1, Cadence: 100 * time.Millisecond},
+		{Workers: 1, Cadence: time.Minute},
+	}
+
+	result, err := ApplyJobOverrides(jobs, lookupFromMap(map[string]string{
+		"JOB_VOTE_DRAIN_WORKERS":           "4",
+		"JOB_SEND_SIGNING_REQUEST_CADENCE": " 1s ",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result[0].Workers)
+	assert.Equal(t, 2*time.Second, result[0].Cadence)
+	assert.Equal(t, 1, result[1].Workers)
+	assert.Equal(t, time.Second, result[1].Cadence)
+	assert.Equal(t, jobs[2], result[2])
+	// the input is not modified
+	assert.Equal(t, 1, jobs[0].Workers)
+
+	result, err = ApplyJobOverrides(jobs, lookupFromMap(map[string]string{
+		"JOB_VOTE_DRAIN_WORKERS": "0",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result[0].Workers)
+}
+
+func TestApplyJobOverrides_Invalid(t *testing.T) {
+	jobs := []Job{{Name: "vote-drain", Workers: 1, Cadence: 2 * time.Second}}
+
+	for _, env := range []map[string]string{
+		{"JOB_VOTE_DRAIN_WORKERS": "many"},
+		{"JOB_VOTE_DRAIN_WORKERS": "-1"},
+		{"JOB_VOTE_DRAIN_WORKERS": "33"},
+		{"JOB_VOTE_DRAIN_CADENCE": "2"},
+		{"JOB_VOTE_DRAIN_CADENCE": "1ms"},
+		{"JOB_VOTE_DRAIN_CADENCE": "25h"},
+	} {
+		_, err := ApplyJobOverrides(jobs, lookupFromMap(env))
+		assert.Error(t, err, env)
+	}
+}
@@ -2,19 +2,27 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
 	"time"
 
 	"github.com/brave-intl/bat-go/libs/clients"
 	errorutils "github.com/brave-intl/bat-go/libs/errors"
 	"github.com/brave-intl/bat-go/libs/logging"
 	sentry "github.com/getsentry/sentry-go"
+	"github.com/rs/zerolog"
 )
 
+// ErrJobPanicked - the job function panicked during a run
+var ErrJobPanicked = errors.New("job panicked")
+
 // JobFunc - type that defines what a Job Function should look like
 type JobFunc func(context.Context) (bool, error)
 
 // Job - Structure defining what a common job meta-information
 type Job struct {
+	Name    string
 	Func    JobFunc
 	Workers int
 	Cadence time.Duration
@@ -27,9 +35,24 @@ type JobService interface {
 
 // JobWorker - a job worker
 func JobWorker(ctx context.Context, job func(context.Context) (bool, error), duration time.Duration) {
+	MonitoredJobWorker(ctx, nil, "", job, duration)
+}
+
+// MonitoredJobWorker - a job worker which records each run of the job under name in monitor
+// the worker exits when ctx is done or the monitor is drained, an in-flight run is never interrupted
+func MonitoredJobWorker(ctx context.Context, monitor *JobMonitor, name string, job func(context.Context) (bool, error), duration time.Duration) {
 	logger := logging.Logger(ctx, "service.JobWorker")
 	for {
-		_, err := job(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		if !monitor.start(name) {
+			return
+		}
+		err := runJob(ctx, logger, job)
+		monitor.finish(name, err)
 		if err != nil {
 			log := logger.Error().Err(err)
 			httpError, ok := err.(*errorutils.ErrorBundle)
@@ -45,6 +68,27 @@ func JobWorker(ctx context.Context, job func(context.Context) (bool, error), dur
 			sentry.CaptureException(err)
 		}
 		// regardless if attempted or not, wait for the duration until retrying
-		<-time.After(duration)
+		select {
+		case <-ctx.Done():
+			return
+		case <-monitor.stopped():
+			return
+		case <-time.After(duration):
+		}
 	}
 }
+
+// runJob runs the job once, recovering from a panic so that the worker keeps running
+func runJob(ctx context.Context, logger *zerolog.Logger, job func(context.Context) (bool, error)) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			logger.Error().
+				Str("panic", fmt.Sprintf("%v", rec)).
+				Str("stack", string(debug.Stack())).
+				Msg("panic recovered in job run")
+			err = fmt.Errorf("%w: %v", ErrJobPanicked, rec)
+		}
+	}()
+	_, err = job(ctx)
+	return err
+}
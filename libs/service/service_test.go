@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitoredJobWorker_RecoversFromPanic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := make(chan int, 1)
+	n := 0
+	job := func(context.Context) (bool, error) {
+		n++
+		select {
+		case calls <- n:
+		default:
+		}
+		if n == 1 {
+			panic("boom")
+		}
+		return true, nil
+	}
+
+	monitor := NewJobMonitor()
+	name := monitor.Register(Job{Name: "panics", Workers: 1, Cadence: time.Millisecond})
+	go MonitoredJobWorker(ctx, monitor, name, job, time.Millisecond)
+
+	// the first run panics, the worker must keep running the job afterwards
+	for call := 0; call < 2; {
+		select {
+		case call = <-calls:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "job worker stopped running after a panic")
+		}
+	}
+
+	status := monitor.Status()
+	require.Len(t, status, 1)
+	assert.Contains(t, status[0].LastError, ErrJobPanicked.Error())
+}
+
+func TestMonitoredJobWorker_DrainFinishesInFlightRun(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	job := func(context.Context) (bool, error) {
+		close(started)
+		<-release
+		return true, nil
+	}
+
+	monitor := NewJobMonitor()
+	name := monitor.Register(Job{Name: "settle", Workers: 1, Cadence: time.Hour})
+	go func() {
+		MonitoredJobWorker(context.Background(), monitor, name, job, time.Hour)
+		close(finished)
+	}()
+
+	<-started
+	go func() {
+		<-time.After(10 * time.Millisecond)
+		close(release)
+	}()
+	assert.Nil(t, monitor.Drain(5*time.Second))
+
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "job worker did not exit after drain")
+	}
+	assert.NotNil(t, monitor.Status()[0].LastSuccess)
+}
@@ -9,11 +9,12 @@ import (
 )
 
 // GetWallet returns the wallet corresponding to the passed wallet info
-func GetWallet(ctx context.Context, info wallet.Info) (wallet.Wallet, error) {
+// uphold options, such as uphold.WithBaseURL, are applied to uphold wallets
+func GetWallet(ctx context.Context, info wallet.Info, upholdOpts ...uphold.Option) (wallet.Wallet, error) {
 	switch info.Provider {
 	case "uphold":
 		// anon card case
-		uW, err := uphold.FromWalletInfo(ctx, info)
+		uW, err := uphold.FromWalletInfo(ctx, info, upholdOpts...)
 		if err != nil {
 			return uW, err
 		}
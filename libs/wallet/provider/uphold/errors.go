@@ -3,6 +3,7 @@ package uphold
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 )
 
@@ -60,6 +61,7 @@ type upholdError struct {
 	ValidationErrors upholdValidationErrors `json:"errors,omitempty"`
 	Data             json.RawMessage        `json:",omitempty"`
 	RequestID        string                 `json:"requestId,omitempty"`
+	StatusCode       int                    `json:"-"`
 }
 
 // Code - implement coded interface
@@ -119,6 +121,29 @@ func (uhErr upholdError) ForbiddenError() bool {
 	return uhErr.Code == "forbidden"
 }
 
+func (uhErr upholdError) Transient() bool {
+	return isTransientStatus(uhErr.StatusCode)
+}
+
+// upholdStatusError is returned for unsuccessful responses whose body is not an uphold error
+type upholdStatusError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (se upholdStatusError) Transient() bool {
+	return isTransientStatus(se.StatusCode)
+}
+
+func (se upholdStatusError) Error() string {
+	return fmt.Sprintf("Error %d, %s", se.StatusCode, se.Body)
+}
+
+// isTransientStatus checks for rate limiting and server errors, which may succeed if retried
+func isTransientStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
 func (uhErr upholdError) String() string {
 	if uhErr.InsufficientBalance() {
 		for _, ae := range uhErr.ValidationErrors.DenominationErrors.ValidationErrors.AmountError {
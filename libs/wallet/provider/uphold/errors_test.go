@@ -77,3 +77,33 @@ func TestInvalidSignature(t *testing.T) {
 		t.Error("Incorrect resulting error string")
 	}
 }
+
+func TestTransient(t *testing.T) {
+	errJSON := []byte(`{"code":"internal_server_error"}`)
+	var uhErr upholdError
+	err := json.Unmarshal(errJSON, &uhErr)
+	if err != nil {
+		t.Error("Unexpected error during uphold error unmarshal")
+	}
+
+	uhErr.StatusCode = 503
+	if !uhErr.Transient() {
+		t.Error("Expected server error to be transient")
+	}
+	uhErr.StatusCode = 429
+	if !uhErr.Transient() {
+		t.Error("Expected rate limit to be transient")
+	}
+	uhErr.StatusCode = 400
+	if uhErr.Transient() {
+		t.Error("Expected bad request to not be transient")
+	}
+
+	statusErr := upholdStatusError{StatusCode: 502, Body: []byte("bad gateway")}
+	if !statusErr.Transient() {
+		t.Error("Expected bad gateway to be transient")
+	}
+	if statusErr.Error() != "Error 502, bad gateway" {
+		t.Error("Incorrect resulting error string")
+	}
+}
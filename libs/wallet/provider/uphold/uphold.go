@@ -20,6 +20,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/brave-intl/bat-go/libs/altcurrency"
@@ -48,6 +50,29 @@ type Wallet struct {
 	walletutils.Info
 	PrivKey crypto.Signer
 	PubKey  httpsignature.Verifier
+	// apiBase overrides the uphold api base url for this wallet when set
+	apiBase string
+	// maxMessageLength limits the characters in a transaction message when set, overriding UPHOLD_MAX_MESSAGE_LENGTH
+	maxMessageLength int
+}
+
+// Option configures an uphold wallet
+type Option func(*Wallet)
+
+// WithBaseURL points the wallet at an alternative uphold api, such as the sandbox or a mock server
+func WithBaseURL(base string) Option {
+	return func(w *Wallet) {
+		w.apiBase = strings.TrimSuffix(base, "/")
+	}
+}
+
+// WithMaxMessageLength rejects transaction messages longer than maxLength characters before they are signed,
+// uphold does not document a limit so messages are not limited by length unless this or
+// UPHOLD_MAX_MESSAGE_LENGTH is set
+func WithMaxMessageLength(maxLength int) Option {
+	return func(w *Wallet) {
+		w.maxMessageLength = maxLength
+	}
 }
 
 const (
@@ -86,12 +111,24 @@ var (
 		"sandbox": "https://api-sandbox.uphold.com",
 		"prod":    "https://api.uphold.com",
 	}[environment]
+	// upholdAPIBaseOverride replaces the api base url selected by UPHOLD_ENVIRONMENT when set
+	upholdAPIBaseOverride = os.Getenv("UPHOLD_API_BASE_URL")
 	upholdCertFingerprint = map[string]string{
 		"":        sandboxFingerprint, // os.Getenv() will return empty string if not set
 		"sandbox": sandboxFingerprint,
 		"prod":    prodFingerprint,
 	}[environment]
 
+	// upholdMaxMessageLength limits the characters in transaction messages of every wallet when set
+	upholdMaxMessageLength = os.Getenv("UPHOLD_MAX_MESSAGE_LENGTH")
+	// defaultMaxMessageLength is the parsed UPHOLD_MAX_MESSAGE_LENGTH, zero does not limit the length
+	defaultMaxMessageLength int
+
+	// upholdPinValidation is parsed into pinValidation, any value strconv.ParseBool accepts is allowed
+	upholdPinValidation = os.Getenv("UPHOLD_PIN_VALIDATION")
+	// pinValidation opts the linking validation requests in to certificate pinning, these fail closed on a pin mismatch
+	pinValidation bool
+
 	// The client to connect to Uphold servers while performing fingerprint
 	// checks on the server certificates.
 	defaultHTTPClient *http.Client
@@ -104,6 +141,27 @@ func init() {
 	prometheus.MustRegister(countUpholdWalletAccountValidation)
 	prometheus.MustRegister(countUpholdTxDestinationGeo)
 
+	if upholdAPIBaseOverride != "" {
+		upholdAPIBase = strings.TrimSuffix(upholdAPIBaseOverride, "/")
+	}
+
+	// an invalid value must not silently leave validation unpinned
+	if len(upholdPinValidation) > 0 {
+		var err error
+		pinValidation, err = strconv.ParseBool(upholdPinValidation)
+		if err != nil {
+			panic("UPHOLD_PIN_VALIDATION is not a valid boolean")
+		}
+	}
+
+	if len(upholdMaxMessageLength) > 0 {
+		maxLength, err := strconv.Atoi(upholdMaxMessageLength)
+		if err != nil || maxLength < 0 {
+			panic("UPHOLD_MAX_MESSAGE_LENGTH is not a valid length")
+		}
+		defaultMaxMessageLength = maxLength
+	}
+
 	// Default back to BAT_SETTLEMENT_ADDRESS
 	if AnonCardSettlementAddress == "" {
 		AnonCardSettlementAddress = SettlementDestination
@@ -123,25 +181,19 @@ func init() {
 		proxy = nil
 	}
 
-	fingerprintDialer := pindialer.MakeContextDialer(upholdCertFingerprint)
-
 	// Uphold reports HTTP 401 error when connecting with HTTP2, so disable
-	// HTTP/2 via setting TLSNextProto to an empty map. We do not need to set
-	// this field on defaultHTTPClient as we set DialTLSContext without setting
-	// ForceAttemptHTTP2 and that disables HTTP/2 also. But for clarity we
-	// always set TLSNextProto.
+	// HTTP/2 via setting TLSNextProto to an empty map. The pinned transport
+	// already does so, but for clarity we always set TLSNextProto.
 	disableHTTP2 := make(
 		map[string]func(authority string, c *tls.Conn) http.RoundTripper,
 		0,
 	)
+	pinnedTransport := pindialer.NewPinnedTransport(upholdCertFingerprint)
+	pinnedTransport.Proxy = proxy
+	pinnedTransport.TLSNextProto = disableHTTP2
 	defaultHTTPClient = &http.Client{
-		Timeout: httpTimeout,
-		Transport: middleware.InstrumentRoundTripper(
-			&http.Transport{
-				DialTLSContext: fingerprintDialer,
-				Proxy:          proxy,
-				TLSNextProto:   disableHTTP2,
-			}, "uphold"),
+		Timeout:   httpTimeout,
+		Transport: middleware.InstrumentRoundTripper(pinnedTransport, "uphold"),
 	}
 	httpClientNoFP = &http.Client{
 		Timeout: httpTimeout,
@@ -155,7 +207,7 @@ func init() {
 
 // New returns an uphold wallet constructed using the provided parameters
 // NOTE that it does not register a wallet with Uphold if it does not already exist
-func New(ctx context.Context, info walletutils.Info, privKey crypto.Signer, pubKey httpsignature.Verifier) (*Wallet, error) {
+func New(ctx context.Context, info walletutils.Info, privKey crypto.Signer, pubKey httpsignature.Verifier, opts ...Option) (*Wallet, error) {
 	if info.Provider != "uphold" {
 		return nil, errors.New("the wallet provider or deposit account must be uphold")
 	}
@@ -169,15 +221,19 @@ func New(ctx context.Context, info walletutils.Info, privKey crypto.Signer, pubK
 	if !info.AltCurrency.IsValid() {
 		return nil, errors.New("a wallet must have a valid altcurrency")
 	}
-	return &Wallet{
+	w := &Wallet{
 		Info:    info,
 		PrivKey: privKey,
 		PubKey:  pubKey,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w, nil
 }
 
 // FromWalletInfo returns an uphold wallet matching the provided wallet info
-func FromWalletInfo(ctx context.Context, info walletutils.Info) (*Wallet, error) {
+func FromWalletInfo(ctx context.Context, info walletutils.Info, opts ...Option) (*Wallet, error) {
 	var publicKey httpsignature.Ed25519PubKey
 	if len(info.PublicKey) > 0 {
 		var err error
@@ -186,11 +242,23 @@ func FromWalletInfo(ctx context.Context, info walletutils.Info) (*Wallet, error)
 			return nil, err
 		}
 	}
-	return New(ctx, info, ed25519.PrivateKey{}, publicKey)
+	return New(ctx, info, ed25519.PrivateKey{}, publicKey, opts...)
 }
 
 func newRequest(method, path string, body io.Reader) (*http.Request, error) {
-	req, err := http.NewRequest(method, upholdAPIBase+path, body)
+	return newRequestWithBase(upholdAPIBase, method, path, body)
+}
+
+// newRequest creates a request against the wallet's api base url
+func (w *Wallet) newRequest(method, path string, body io.Reader) (*http.Request, error) {
+	if w.apiBase != "" {
+		return newRequestWithBase(w.apiBase, method, path, body)
+	}
+	return newRequest(method, path, body)
+}
+
+func newRequestWithBase(base, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, base+path, body)
 	if err == nil {
 		if len(clientCredentialsToken) > 0 {
 			req.Header.Add("Authorization", "Bearer "+clientCredentialsToken)
@@ -249,9 +317,10 @@ func submit(
 	if resp.StatusCode/100 != 2 {
 		var uhErr upholdError
 		if json.Unmarshal(body, &uhErr) != nil {
-			return nil, resp, fmt.Errorf("Error %d, %s", resp.StatusCode, body)
+			return nil, resp, upholdStatusError{StatusCode: resp.StatusCode, Body: body}
 		}
 		uhErr.RequestID = resp.Header.Get("Request-Id")
+		uhErr.StatusCode = resp.StatusCode
 		return nil, resp, uhErr
 	}
 	return body, resp, nil
@@ -301,12 +370,17 @@ func (w *Wallet) IsUserKYC(ctx context.Context, destination string) (string, boo
 	// Submit the transaction payload.
 	//
 	// As we use the wallet only for validation but not for a payment, skip
-	// fingerprint checks to avoid outages when Uphold change the certificate.
+	// fingerprint checks to avoid outages when Uphold change the certificate,
+	// unless pinning was opted in to with UPHOLD_PIN_VALIDATION.
 	// For payments it is not a problem as they are done in batch and can be
 	// repeated.
+	validationClient := httpClientNoFP
+	if pinValidation {
+		validationClient = defaultHTTPClient
+	}
 	uhResp, err := grantWallet.submitTransaction(
 		ctx,
-		httpClientNoFP,
+		validationClient,
 		transactionB64,
 		false, /*confirm*/
 	)
@@ -384,7 +458,7 @@ func (w *Wallet) signRegistration(label string) (*http.Request, error) {
 		return nil, err
 	}
 
-	req, err := newRequest("POST", "/v0/me/cards", bytes.NewBuffer(payload))
+	req, err := w.newRequest("POST", "/v0/me/cards", bytes.NewBuffer(payload))
 	if err != nil {
 		return nil, err
 	}
@@ -398,6 +472,18 @@ func (w *Wallet) signRegistration(label string) (*http.Request, error) {
 	return req, err
 }
 
+// CheckConnectivity verifies that the uphold api is reachable and accepts the configured credentials
+func CheckConnectivity(ctx context.Context) error {
+	logger := logging.FromContext(ctx)
+
+	req, err := newRequest("GET", "/v0/me", nil)
+	if err != nil {
+		return err
+	}
+	_, _, err = submit(logger, defaultHTTPClient, req.WithContext(ctx))
+	return err
+}
+
 // Register a wallet with Uphold with label
 func (w *Wallet) Register(ctx context.Context, label string) error {
 	logger := logging.FromContext(ctx)
@@ -436,7 +522,7 @@ func (w *Wallet) SubmitRegistration(ctx context.Context, registrationB64 string)
 		return err
 	}
 
-	req, err := newRequest("POST", "/v0/me/cards", nil)
+	req, err := w.newRequest("POST", "/v0/me/cards", nil)
 	if err != nil {
 		return err
 	}
@@ -460,6 +546,50 @@ func (w *Wallet) SubmitRegistration(ctx context.Context, registrationB64 string)
 	return nil
 }
 
+// cardsPageSize is the number of cards requested per page when listing cards
+const cardsPageSize = 50
+
+// ResumeRegistration looks for an existing card registered with the wallet's public key and, if found, sets it as
+// the wallet's card. it returns false if there is no such card. Calling it before Register or SubmitRegistration makes
+// a retried registration idempotent, as a card submitted by an earlier attempt is reused rather than registered again.
+// Cards are matched on the key rather than the label, as labels are not unique and a card registered with a different
+// key could never be signed for by this wallet
+func (w *Wallet) ResumeRegistration(ctx context.Context) (bool, error) {
+	logger := logging.FromContext(ctx)
+
+	if w.PubKey == nil || len(w.PubKey.String()) == 0 {
+		return false, errors.New("a public key is required to look up an existing registration")
+	}
+	publicKey := w.PubKey.String()
+
+	for start := 0; ; start += cardsPageSize {
+		req, err := w.newRequest("GET", "/v0/me/cards", nil)
+		if err != nil {
+			return false, err
+		}
+		req.Header.Set("Range", fmt.Sprintf("items=%d-%d", start, start+cardsPageSize-1))
+
+		body, _, err := submit(logger, defaultHTTPClient, req.WithContext(ctx))
+		if err != nil {
+			return false, err
+		}
+
+		var cards []CardDetails
+		if err := json.Unmarshal(body, &cards); err != nil {
+			return false, err
+		}
+		for _, card := range cards {
+			if card.PublicKey == publicKey {
+				w.Info.ProviderID = card.ID.String()
+				return true, nil
+			}
+		}
+		if len(cards) < cardsPageSize {
+			return false, nil
+		}
+	}
+}
+
 // PrepareRegistration returns a b64 encoded serialized signed registration suitable for SubmitRegistration
 func (w *Wallet) PrepareRegistration(label string) (string, error) {
 	req, err := w.signRegistration(label)
@@ -491,6 +621,8 @@ type CardDetails struct {
 	Balance          decimal.Decimal         `json:"balance"`
 	Currency         altcurrency.AltCurrency `json:"currency"`
 	ID               uuid.UUID               `json:"id"`
+	Label            string                  `json:"label,omitempty"`
+	PublicKey        string                  `json:"publicKey,omitempty"`
 	Settings         CardSettings            `json:"settings"`
 }
 
@@ -498,7 +630,7 @@ type CardDetails struct {
 func (w *Wallet) GetCardDetails(ctx context.Context) (*CardDetails, error) {
 	logger := logging.FromContext(ctx)
 
-	req, err := newRequest("GET", "/v0/me/cards/"+w.ProviderID, nil)
+	req, err := w.newRequest("GET", "/v0/me/cards/"+w.ProviderID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -572,7 +704,7 @@ func (w *Wallet) signTransfer(altc altcurrency.AltCurrency, probi decimal.Decima
 		return nil, fmt.Errorf("%w: %s", errorutils.ErrMarshalTransferRequest, err.Error())
 	}
 
-	req, err := newRequest("POST", "/v0/me/cards/"+w.ProviderID+"/transactions?commit=true", bytes.NewBuffer(unsignedTransaction))
+	req, err := w.newRequest("POST", "/v0/me/cards/"+w.ProviderID+"/transactions?commit=true", bytes.NewBuffer(unsignedTransaction))
 	if err != nil {
 		return nil, fmt.Errorf("%w: %s", errorutils.ErrCreateTransferRequest, err.Error())
 	}
@@ -588,8 +720,40 @@ func (w *Wallet) signTransfer(altc altcurrency.AltCurrency, probi decimal.Decima
 	return req, nil
 }
 
+var (
+	// ErrTransactionMessageTooLong is returned when a transaction message exceeds the wallet's maximum message length
+	ErrTransactionMessageTooLong = errors.New("transaction message is too long")
+	// ErrTransactionMessageInvalid is returned when a transaction message contains control characters or invalid utf-8
+	ErrTransactionMessageInvalid = errors.New("transaction message must be valid utf-8 without control characters")
+)
+
+// validateTransactionMessage checks a transaction message locally, so that a transaction is not signed
+// with a message uphold will reject, maxLength of zero does not limit the length
+func validateTransactionMessage(message string, maxLength int) error {
+	if !utf8.ValidString(message) {
+		return ErrTransactionMessageInvalid
+	}
+	if maxLength > 0 && utf8.RuneCountInString(message) > maxLength {
+		return fmt.Errorf("%w, it must be at most %d characters", ErrTransactionMessageTooLong, maxLength)
+	}
+	for _, r := range message {
+		if unicode.IsControl(r) {
+			return ErrTransactionMessageInvalid
+		}
+	}
+	return nil
+}
+
 // PrepareTransaction returns a b64 encoded serialized signed transaction suitable for SubmitTransaction
 func (w *Wallet) PrepareTransaction(altcurrency altcurrency.AltCurrency, probi decimal.Decimal, destination string, message string, purpose string, beneficiary *Beneficiary) (string, error) {
+	maxLength := w.maxMessageLength
+	if maxLength == 0 {
+		maxLength = defaultMaxMessageLength
+	}
+	if err := validateTransactionMessage(message, maxLength); err != nil {
+		return "", err
+	}
+
 	req, err := w.signTransfer(altcurrency, probi, destination, message, purpose, beneficiary)
 	if err != nil {
 		return "", err
@@ -926,7 +1090,7 @@ func (w *Wallet) submitTransaction(
 		url = url + "?commit=true"
 	}
 
-	req, err := newRequest("POST", url, nil)
+	req, err := w.newRequest("POST", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -954,7 +1118,7 @@ func (w *Wallet) submitTransaction(
 func (w *Wallet) ConfirmTransaction(ctx context.Context, id string) (*walletutils.TransactionInfo, error) {
 	logger := logging.FromContext(ctx)
 
-	req, err := newRequest("POST", "/v0/me/cards/"+w.ProviderID+"/transactions/"+id+"/commit", nil)
+	req, err := w.newRequest("POST", "/v0/me/cards/"+w.ProviderID+"/transactions/"+id+"/commit", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -980,7 +1144,7 @@ func (w *Wallet) ConfirmTransaction(ctx context.Context, id string) (*walletutil
 func (w *Wallet) GetTransaction(ctx context.Context, id string) (*walletutils.TransactionInfo, error) {
 	logger := logging.FromContext(ctx)
 
-	req, err := newRequest("GET", "/v0/me/transactions/"+id, nil)
+	req, err := w.newRequest("GET", "/v0/me/transactions/"+id, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -998,84 +1162,198 @@ func (w *Wallet) GetTransaction(ctx context.Context, id string) (*walletutils.Tr
 	return uhResp.ToTransactionInfo(), nil
 }
 
-// ListTransactions for this wallet, pagination not yet supported
-func (w *Wallet) ListTransactions(ctx context.Context, limit int, startDate time.Time) ([]walletutils.TransactionInfo, error) {
+// TransactionDetailUser is the uphold user behind one side of a transaction
+type TransactionDetailUser struct {
+	ID                 string `json:"id"`
+	CitizenshipCountry string `json:"citizenshipCountry"`
+	IdentityCountry    string `json:"identityCountry"`
+	ResidenceCountry   string `json:"residenceCountry"`
+}
+
+// TransactionDetailNode is the account behind one side of a transaction
+type TransactionDetailNode struct {
+	Type string                `json:"type"`
+	ID   string                `json:"id"`
+	User TransactionDetailUser `json:"user"`
+}
+
+// TransactionDetailParty is the origin or destination of a transaction, amounts are in Currency
+type TransactionDetailParty struct {
+	Type        string                `json:"type"`
+	CardID      string                `json:"CardId,omitempty"`
+	Node        TransactionDetailNode `json:"node"`
+	Description string                `json:"description"`
+	Currency    string                `json:"currency"`
+	Amount      decimal.Decimal       `json:"amount"`
+	Base        decimal.Decimal       `json:"base"`
+	ExchangeFee decimal.Decimal       `json:"commission"`
+	TransferFee decimal.Decimal       `json:"fee"`
+	Rate        decimal.Decimal       `json:"rate"`
+	IsMember    bool                  `json:"isMember"`
+}
+
+// TransactionDetailDenomination is the amount and currency a transaction was requested in
+type TransactionDetailDenomination struct {
+	Amount   decimal.Decimal `json:"amount"`
+	Currency string          `json:"currency"`
+	Pair     string          `json:"pair"`
+	Rate     decimal.Decimal `json:"rate"`
+}
+
+// TransactionDetailFee is a single fee charged on a transaction
+type TransactionDetailFee struct {
+	Type       string          `json:"type"`
+	Target     string          `json:"target"`
+	Currency   string          `json:"currency"`
+	Amount     decimal.Decimal `json:"amount"`
+	Percentage decimal.Decimal `json:"percentage"`
+}
+
+// TransactionDetailParams are the exchange parameters a transaction was quoted with
+type TransactionDetailParams struct {
+	Currency string          `json:"currency"`
+	Margin   decimal.Decimal `json:"margin"`
+	Pair     string          `json:"pair"`
+	Rate     decimal.Decimal `json:"rate"`
+	TTL      int64           `json:"ttl"`
+	Type     string          `json:"type"`
+}
+
+// TransactionDetail is the complete uphold record of a transaction, including fees and exchange details
+type TransactionDetail struct {
+	ID           string                        `json:"id"`
+	Type         string                        `json:"type"`
+	Status       string                        `json:"status"`
+	Message      string                        `json:"message"`
+	Reference    string                        `json:"reference"`
+	CreatedAt    time.Time                     `json:"createdAt"`
+	Denomination TransactionDetailDenomination `json:"denomination"`
+	Origin       TransactionDetailParty        `json:"origin"`
+	Destination  TransactionDetailParty        `json:"destination"`
+	Fees         []TransactionDetailFee        `json:"fees"`
+	Params       TransactionDetailParams       `json:"params"`
+}
+
+// TotalFees returns the sum of the fees charged on the transaction in currency
+func (td TransactionDetail) TotalFees(currency string) decimal.Decimal {
+	total := decimal.Zero
+	for _, fee := range td.Fees {
+		if fee.Currency == currency {
+			total = total.Add(fee.Amount)
+		}
+	}
+	return total
+}
+
+// GetTransactionDetail returns the complete detail of the transaction with id, unlike GetTransaction which
+// only returns the fields common to all wallet providers
+func (w *Wallet) GetTransactionDetail(ctx context.Context, id string) (*TransactionDetail, error) {
 	logger := logging.FromContext(ctx)
 
-	var out []walletutils.TransactionInfo
-	if limit > 0 {
-		out = make([]walletutils.TransactionInfo, 0, limit)
+	req, err := w.newRequest("GET", "/v0/me/transactions/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+	body, _, err := submit(logger, defaultHTTPClient, req.WithContext(ctx))
+	if err != nil {
+		return nil, err
 	}
-	var totalTransactions int
-	toExit := false
-	for {
-		req, err := newRequest("GET", "/v0/me/cards/"+w.ProviderID+"/transactions", nil)
-		if err != nil {
-			return nil, err
-		}
 
-		start := len(out)
-		stop := start + batchSize
-		if limit > 0 && stop >= limit {
-			stop = limit - 1
-		}
-		if totalTransactions != 0 && stop >= totalTransactions {
-			stop = totalTransactions - 1
-		}
+	var detail TransactionDetail
+	if err := json.Unmarshal(body, &detail); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
 
-		req.Header.Set("Range", fmt.Sprintf("items=%d-%d", start, stop))
-		var body []byte
-		var resp *http.Response
-		for i := 0; i < listTransactionsRetries; i++ {
-			body, resp, err = submit(logger, defaultHTTPClient, req)
-			if nerr, ok := err.(net.Error); ok && nerr.Temporary() {
-				logger.Debug().
-					Str("path", "github.com/brave-intl/bat-go/wallet/provider/uphold").
-					Str("type", "net.Error").
-					Msg("Temporary error occurred, retrying")
-				continue
-			}
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
+// ListTransactionsPaged returns up to limit transactions of this wallet starting at offset, newest first, along
+// with the total number of transactions. Pages are requested with the uphold Range header and the total is read
+// from the Content-Range header of the response, a page holds at most 50 transactions
+func (w *Wallet) ListTransactionsPaged(ctx context.Context, offset, limit int) ([]walletutils.TransactionInfo, int, error) {
+	logger := logging.FromContext(ctx)
+
+	if offset < 0 || limit <= 0 {
+		return nil, 0, errors.New("offset must not be negative and limit must be positive")
+	}
+	if limit > batchSize {
+		limit = batchSize
+	}
 
-		contentRange := resp.Header.Get("Content-Range")
-		parts := strings.Split(contentRange, "/")
-		if len(parts) != 2 {
-			return nil, errors.New("invalid Content-Range header returned")
+	req, err := w.newRequest("GET", "/v0/me/cards/"+w.ProviderID+"/transactions", nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("items=%d-%d", offset, offset+limit-1))
+
+	var body []byte
+	var resp *http.Response
+	for i := 0; i < listTransactionsRetries; i++ {
+		body, resp, err = submit(logger, defaultHTTPClient, req.WithContext(ctx))
+		if nerr, ok := err.(net.Error); ok && nerr.Temporary() {
+			logger.Debug().
+				Str("path", "github.com/brave-intl/bat-go/wallet/provider/uphold").
+				Str("type", "net.Error").
+				Msg("Temporary error occurred, retrying")
+			continue
 		}
+		break
+	}
+	if err != nil {
+		return nil, 0, err
+	}
 
-		tmp, err := strconv.Atoi(parts[1])
-		if err != nil {
-			return nil, err
+	contentRange := resp.Header.Get("Content-Range")
+	parts := strings.Split(contentRange, "/")
+	if len(parts) != 2 {
+		return nil, 0, errors.New("invalid Content-Range header returned")
+	}
+	total, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var uhResp []upholdTransactionResponse
+	err = json.Unmarshal(body, &uhResp)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	page := make([]walletutils.TransactionInfo, 0, len(uhResp))
+	for i := 0; i < len(uhResp); i++ {
+		page = append(page, *uhResp[i].ToTransactionInfo())
+	}
+	return page, total, nil
+}
+
+// ListTransactions for this wallet, newest first, stopping at limit transactions or the first created
+// before startDate. A limit of zero or less lists every transaction
+func (w *Wallet) ListTransactions(ctx context.Context, limit int, startDate time.Time) ([]walletutils.TransactionInfo, error) {
+	var out []walletutils.TransactionInfo
+	if limit > 0 {
+		out = make([]walletutils.TransactionInfo, 0, limit)
+	}
+	for {
+		pageSize := batchSize
+		if limit > 0 && limit-len(out) < pageSize {
+			pageSize = limit - len(out)
 		}
-		totalTransactions = int(tmp)
 
-		var uhResp []upholdTransactionResponse
-		err = json.Unmarshal(body, &uhResp)
+		page, total, err := w.ListTransactionsPaged(ctx, len(out), pageSize)
 		if err != nil {
 			return nil, err
 		}
 
-		for i := 0; i < len(uhResp); i++ {
-			txInfo := *uhResp[i].ToTransactionInfo()
+		for _, txInfo := range page {
 			if txInfo.Time.Before(startDate) {
-				toExit = true
-				break
+				return out, nil
 			}
 			out = append(out, txInfo)
-			if len(out) == limit {
-				break
-			}
 		}
 
-		if len(out) == limit || len(out) == totalTransactions || toExit {
-			break
+		if len(page) == 0 || len(out) >= total || (limit > 0 && len(out) >= limit) {
+			return out, nil
 		}
 	}
-	return out, nil
 }
 
 // GetBalance returns the last known balance, if refresh is true then the current balance is fetched
@@ -1122,7 +1400,7 @@ func (w *Wallet) CreateCardAddress(ctx context.Context, network string) (string,
 		return "", err
 	}
 
-	req, err := newRequest("POST", fmt.Sprintf("/v0/me/cards/%s/addresses", w.ProviderID), bytes.NewBuffer(payload))
+	req, err := w.newRequest("POST", fmt.Sprintf("/v0/me/cards/%s/addresses", w.ProviderID), bytes.NewBuffer(payload))
 	if err != nil {
 		return "", err
 	}
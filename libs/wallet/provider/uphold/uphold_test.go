@@ -3,10 +3,14 @@ package uphold
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -73,6 +77,255 @@ func TestRegister(t *testing.T) {
 	}
 }
 
+func TestResumeRegistration(t *testing.T) {
+	ctx := context.Background()
+
+	publicKey, _, err := httpsignature.GenerateEd25519Key(nil)
+	assert.NilError(t, err)
+	otherKey, _, err := httpsignature.GenerateEd25519Key(nil)
+	assert.NilError(t, err)
+
+	existing := uuid.NewV4()
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v0/me/cards", r.URL.Path)
+		ranges = append(ranges, r.Header.Get("Range"))
+
+		cards := make([]*CardDetails, 0, cardsPageSize)
+		if len(ranges) == 1 {
+			// a full first page sharing the label but not the key, the registered card is on the second page
+			for i := 0; i < cardsPageSize; i++ {
+				cards = append(cards, &CardDetails{
+					ID:        uuid.NewV4(),
+					Label:     "settlement",
+					PublicKey: otherKey.String(),
+					Currency:  altcurrency.BAT,
+				})
+			}
+		} else {
+			cards = append(cards, &CardDetails{
+				ID:        existing,
+				Label:     "settlement",
+				PublicKey: publicKey.String(),
+				Currency:  altcurrency.BAT,
+			})
+		}
+		b, err := json.Marshal(cards)
+		assert.NilError(t, err)
+		_, err = w.Write(b)
+		assert.NilError(t, err)
+	}))
+	defer srv.Close()
+
+	w := &Wallet{apiBase: srv.URL, PubKey: publicKey}
+	found, err := w.ResumeRegistration(ctx)
+	assert.NilError(t, err)
+	assert.Assert(t, found, "already registered card should be found")
+	assert.Equal(t, existing.String(), w.ProviderID)
+	assert.DeepEqual(t, []string{"items=0-49", "items=50-99"}, ranges)
+
+	unregistered, _, err := httpsignature.GenerateEd25519Key(nil)
+	assert.NilError(t, err)
+	w = &Wallet{apiBase: srv.URL, PubKey: unregistered}
+	ranges = nil
+	found, err = w.ResumeRegistration(ctx)
+	assert.NilError(t, err)
+	assert.Assert(t, !found, "unregistered key should not be found")
+	assert.Equal(t, "", w.ProviderID)
+}
+
+func TestWithBaseURL(t *testing.T) {
+	ctx := context.Background()
+
+	cardID := uuid.NewV4()
+	var requested string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		b, err := json.Marshal(&CardDetails{ID: cardID, Currency: altcurrency.BAT})
+		assert.NilError(t, err)
+		_, err = w.Write(b)
+		assert.NilError(t, err)
+	}))
+	defer srv.Close()
+
+	var info wallet.Info
+	info.Provider = "uphold"
+	info.ProviderID = cardID.String()
+	{
+		tmp := altcurrency.BAT
+		info.AltCurrency = &tmp
+	}
+
+	w, err := FromWalletInfo(ctx, info, WithBaseURL(srv.URL+"/"))
+	assert.NilError(t, err)
+
+	details, err := w.GetCardDetails(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, "/v0/me/cards/"+cardID.String(), requested)
+	assert.Equal(t, cardID, details.ID)
+}
+
+func TestPrepareTransactionValidatesMessage(t *testing.T) {
+	var info wallet.Info
+	info.Provider = "uphold"
+	info.ProviderID = uuid.NewV4().String()
+	{
+		tmp := altcurrency.BAT
+		info.AltCurrency = &tmp
+	}
+
+	publicKey, privateKey, err := httpsignature.GenerateEd25519Key(nil)
+	assert.NilError(t, err)
+	w := &Wallet{Info: info, PrivKey: privateKey, PubKey: publicKey}
+
+	destination := uuid.NewV4().String()
+	value := altcurrency.BAT.ToProbi(decimal.NewFromFloat(1))
+
+	_, err = w.PrepareTransaction(altcurrency.BAT, value, destination, "payout for march", "", nil)
+	assert.NilError(t, err)
+
+	// the length is not limited unless configured
+	_, err = w.PrepareTransaction(altcurrency.BAT, value, destination, strings.Repeat("a", 500), "", nil)
+	assert.NilError(t, err)
+
+	// UPHOLD_MAX_MESSAGE_LENGTH limits every wallet
+	defaultMaxMessageLength = 140
+	_, err = w.PrepareTransaction(altcurrency.BAT, value, destination, strings.Repeat("a", 141), "", nil)
+	assert.Assert(t, errors.Is(err, ErrTransactionMessageTooLong), err)
+	defaultMaxMessageLength = 0
+
+	WithMaxMessageLength(140)(w)
+	_, err = w.PrepareTransaction(altcurrency.BAT, value, destination, strings.Repeat("a", 141), "", nil)
+	assert.Assert(t, errors.Is(err, ErrTransactionMessageTooLong), err)
+
+	// multi-byte characters are counted as a single character
+	_, err = w.PrepareTransaction(altcurrency.BAT, value, destination, strings.Repeat("€", 140), "", nil)
+	assert.NilError(t, err)
+
+	for _, message := range []string{"line\nbreak", "tab\tseparated", "null\x00byte", "invalid \xff utf-8"} {
+		_, err = w.PrepareTransaction(altcurrency.BAT, value, destination, message, "", nil)
+		assert.Assert(t, errors.Is(err, ErrTransactionMessageInvalid), message)
+	}
+}
+
+func TestGetTransactionDetail(t *testing.T) {
+	ctx := context.Background()
+
+	recorded, err := os.ReadFile("testdata/transaction.json")
+	assert.NilError(t, err)
+
+	txID := "4c8d2b1a-72a6-4e1b-9a4f-0b9f3b6b9f21"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v0/me/transactions/"+txID, r.URL.Path)
+		_, err := w.Write(recorded)
+		assert.NilError(t, err)
+	}))
+	defer srv.Close()
+
+	w := &Wallet{apiBase: srv.URL}
+	detail, err := w.GetTransactionDetail(ctx, txID)
+	assert.NilError(t, err)
+
+	assert.Equal(t, txID, detail.ID)
+	assert.Equal(t, "transfer", detail.Type)
+	assert.Equal(t, "completed", detail.Status)
+	assert.Equal(t, "payout for march", detail.Message)
+	assert.Equal(t, "", detail.Reference)
+	assert.Assert(t, detail.CreatedAt.Equal(time.Date(2023, 3, 1, 14, 2, 11, 258000000, time.UTC)))
+
+	assert.Assert(t, detail.Denomination.Amount.Equal(decimal.NewFromInt(25)))
+	assert.Equal(t, "BATBAT", detail.Denomination.Pair)
+
+	assert.Equal(t, "0a4b8c1d-5b36-4c8e-8d2f-3f8a2c9e6b10", detail.Origin.CardID)
+	assert.Assert(t, detail.Origin.Amount.Equal(decimal.RequireFromString("25.75")))
+	assert.Assert(t, detail.Origin.ExchangeFee.Equal(decimal.RequireFromString("0.50")))
+	assert.Assert(t, detail.Origin.TransferFee.Equal(decimal.RequireFromString("0.25")))
+
+	assert.Equal(t, "d5a1f3c2-8e4b-4b7a-9c6d-2e1f0a9b8c77", detail.Destination.CardID)
+	assert.Equal(t, "US", detail.Destination.Node.User.IdentityCountry)
+	assert.Assert(t, detail.Destination.Amount.Equal(decimal.NewFromInt(25)))
+
+	assert.Equal(t, 2, len(detail.Fees))
+	assert.Equal(t, "exchange", detail.Fees[0].Type)
+	assert.Assert(t, detail.Fees[0].Percentage.Equal(decimal.NewFromInt(2)))
+	assert.Assert(t, detail.TotalFees("BAT").Equal(decimal.RequireFromString("0.75")))
+	assert.Assert(t, detail.TotalFees("USD").IsZero())
+
+	assert.Equal(t, int64(18000), detail.Params.TTL)
+	assert.Equal(t, "internal", detail.Params.Type)
+}
+
+func TestListTransactionsPaged(t *testing.T) {
+	ctx := context.Background()
+
+	const total = 120
+	created := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v0/me/cards/card/transactions", r.URL.Path)
+		ranges = append(ranges, r.Header.Get("Range"))
+
+		var start, stop int
+		_, err := fmt.Sscanf(r.Header.Get("Range"), "items=%d-%d", &start, &stop)
+		assert.NilError(t, err)
+		if stop >= total {
+			stop = total - 1
+		}
+
+		txs := []map[string]interface{}{}
+		for i := start; i <= stop; i++ {
+			txs = append(txs, map[string]interface{}{
+				"id":     fmt.Sprintf("tx-%d", i),
+				"status": "completed",
+				// newest first, one transaction an hour
+				"createdAt":    created.Add(-time.Duration(i) * time.Hour).Format("2006-01-02T15:04:05.000Z"),
+				"denomination": map[string]string{"amount": "1", "currency": "BAT"},
+			})
+		}
+		b, err := json.Marshal(txs)
+		assert.NilError(t, err)
+		w.Header().Set("Content-Range", fmt.Sprintf("items %d-%d/%d", start, stop, total))
+		_, err = w.Write(b)
+		assert.NilError(t, err)
+	}))
+	defer srv.Close()
+
+	w := &Wallet{Info: wallet.Info{ProviderID: "card"}, apiBase: srv.URL}
+
+	page, count, err := w.ListTransactionsPaged(ctx, 100, 30)
+	assert.NilError(t, err)
+	assert.Equal(t, total, count)
+	assert.Equal(t, 20, len(page))
+	assert.Equal(t, "tx-100", page[0].ID)
+	assert.DeepEqual(t, []string{"items=100-129"}, ranges)
+
+	// pages are capped at the uphold maximum
+	ranges = nil
+	page, _, err = w.ListTransactionsPaged(ctx, 0, 500)
+	assert.NilError(t, err)
+	assert.Equal(t, batchSize, len(page))
+	assert.DeepEqual(t, []string{"items=0-49"}, ranges)
+
+	ranges = nil
+	txs, err := w.ListTransactions(ctx, 0, time.Unix(0, 0))
+	assert.NilError(t, err)
+	assert.Equal(t, total, len(txs))
+	assert.DeepEqual(t, []string{"items=0-49", "items=50-99", "items=100-149"}, ranges)
+
+	ranges = nil
+	txs, err = w.ListTransactions(ctx, 60, time.Unix(0, 0))
+	assert.NilError(t, err)
+	assert.Equal(t, 60, len(txs))
+	assert.Equal(t, "tx-59", txs[59].ID)
+	assert.DeepEqual(t, []string{"items=0-49", "items=50-59"}, ranges)
+
+	ranges = nil
+	txs, err = w.ListTransactions(ctx, 0, created.Add(-10*time.Hour))
+	assert.NilError(t, err)
+	assert.Equal(t, 11, len(txs))
+	assert.DeepEqual(t, []string{"items=0-49"}, ranges)
+}
+
 func TestDecodeTransaction(t *testing.T) {
 	var info wallet.Info
 	info.Provider = "uphold"
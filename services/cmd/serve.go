@@ -72,7 +72,7 @@ func SetupRouter(ctx context.Context) *chi.Mux {
 		r.Use(
 			hlog.NewHandler(*logger),
 			hlog.UserAgentHandler("user_agent"),
-			hlog.RequestIDHandler("req_id", "Request-Id"),
+			hlog.RequestIDHandler("req_id", middleware.RequestIDResponseHeader),
 			middleware.RequestLogger(logger))
 
 		logger.Info().
@@ -104,7 +104,17 @@ func SetupJobWorkers(ctx context.Context, jobs []srv.Job) error {
 	}
 
 	if enableJobWorkers {
+		// allow ops to tune workers and cadence per job from the environment
+		jobs, err = srv.ApplyJobOverrides(jobs, os.LookupEnv)
+		if err != nil {
+			return err
+		}
 		for _, job := range jobs {
+			logger.Info().
+				Str("job", job.Name).
+				Int("workers", job.Workers).
+				Str("cadence", job.Cadence.String()).
+				Msg("job worker settings")
 			// iterate over jobs
 			for i := 0; i < job.Workers; i++ {
 				// spin up a job worker for each worker
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/cors"
+)
+
+const (
+	publicCORSOriginsEnv = "PUBLIC_CORS_ALLOWED_ORIGINS"
+	publicCORSMethodsEnv = "PUBLIC_CORS_ALLOWED_METHODS"
+	publicCORSHeadersEnv = "PUBLIC_CORS_ALLOWED_HEADERS"
+)
+
+var (
+	defaultPublicCORSMethods = []string{http.MethodGet, http.MethodPost}
+	defaultPublicCORSHeaders = []string{"Accept", "Content-Type", "Digest", "Signature"}
+)
+
+// newPublicCORSMwr creates the cors middleware for the public routers from the allowed origins, methods
+// and headers held in the environment, when no origins are configured cross origin requests are denied
+// except in the local environment where any origin is allowed to ease development
+func newPublicCORSMwr(env string, getenv func(string) string) func(next http.Handler) http.Handler {
+	origins := splitEnvList(getenv(publicCORSOriginsEnv))
+	if len(origins) == 0 {
+		if env != "local" {
+			// without cors headers browsers refuse cross origin requests
+			return func(next http.Handler) http.Handler { return next }
+		}
+		origins = []string{"*"}
+	}
+
+	methods := splitEnvList(getenv(publicCORSMethodsEnv))
+	if len(methods) == 0 {
+		methods = defaultPublicCORSMethods
+	}
+
+	headers := splitEnvList(getenv(publicCORSHeadersEnv))
+	if len(headers) == 0 {
+		headers = defaultPublicCORSHeaders
+	}
+
+	return cors.Handler(cors.Options{
+		AllowedOrigins:   origins,
+		AllowedMethods:   methods,
+		AllowedHeaders:   headers,
+		AllowCredentials: false,
+		MaxAge:           300,
+	})
+}
+
+func splitEnvList(value string) []string {
+	var result []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
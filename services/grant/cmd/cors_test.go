@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPublicCORSRouter(env string, vars map[string]string) *chi.Mux {
+	r := chi.NewRouter()
+	r.Mount("/v1/promotions", newPublicCORSMwr(env, func(key string) string { return vars[key] })(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	))
+	return r
+}
+
+func preflight(r http.Handler, origin, method string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, "/v1/promotions", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	rw := httptest.NewRecorder()
+	r.ServeHTTP(rw, req)
+	return rw
+}
+
+func TestPublicCORS_Preflight(t *testing.T) {
+	r := newPublicCORSRouter("production", map[string]string{
+		publicCORSOriginsEnv: "https://a.brave.com, https://b.brave.com",
+	})
+
+	rw := preflight(r, "https://b.brave.com", http.MethodPost)
+	assert.Equal(t, "https://b.brave.com", rw.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, http.MethodPost, rw.Header().Get("Access-Control-Allow-Methods"))
+
+	rw = preflight(r, "https://evil.com", http.MethodPost)
+	assert.Empty(t, rw.Header().Get("Access-Control-Allow-Origin"))
+
+	rw = preflight(r, "https://a.brave.com", http.MethodDelete)
+	assert.Empty(t, rw.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestPublicCORS_ConfiguredMethods(t *testing.T) {
+	r := newPublicCORSRouter("production", map[string]string{
+		publicCORSOriginsEnv: "https://a.brave.com",
+		publicCORSMethodsEnv: "GET",
+	})
+
+	rw := preflight(r, "https://a.brave.com", http.MethodPost)
+	assert.Empty(t, rw.Header().Get("Access-Control-Allow-Origin"))
+
+	rw = preflight(r, "https://a.brave.com", http.MethodGet)
+	assert.Equal(t, "https://a.brave.com", rw.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestPublicCORS_DefaultDeny(t *testing.T) {
+	rw := preflight(newPublicCORSRouter("production", nil), "https://a.brave.com", http.MethodGet)
+	assert.Empty(t, rw.Header().Get("Access-Control-Allow-Origin"))
+
+	rw = preflight(newPublicCORSRouter("local", nil), "http://localhost:8080", http.MethodGet)
+	assert.Equal(t, "*", rw.Header().Get("Access-Control-Allow-Origin"))
+}
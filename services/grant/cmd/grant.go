@@ -3,12 +3,14 @@ package cmd
 import (
 	"context"
 	"encoding/base64"
-	"fmt"
+	"errors"
 	"net/http"
 	_ "net/http/pprof" // Enable profiling.
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/asaskevich/govalidator"
@@ -28,6 +30,7 @@ import (
 	"github.com/brave-intl/bat-go/libs/handlers"
 	"github.com/brave-intl/bat-go/libs/logging"
 	"github.com/brave-intl/bat-go/libs/middleware"
+	"github.com/brave-intl/bat-go/libs/sentryutil"
 	srv "github.com/brave-intl/bat-go/libs/service"
 	servicescmd "github.com/brave-intl/bat-go/services/cmd"
 	"github.com/brave-intl/bat-go/services/grant"
@@ -63,6 +66,11 @@ func init() {
 		Bind("enable-job-workers").
 		Env("ENABLE_JOB_WORKERS")
 
+	flagBuilder.Flag().Duration("job-drain-timeout", 30*time.Second,
+		"how long to wait for in-flight job runs to finish on shutdown").
+		Bind("job-drain-timeout").
+		Env("JOB_DRAIN_TIMEOUT")
+
 	flagBuilder.Flag().Bool("disable-disconnect", false,
 		"disable custodian ability to disconnect rewards wallets").
 		Bind("disable-disconnect").
@@ -337,7 +345,7 @@ func setupRouter(ctx context.Context, logger *zerolog.Logger) (context.Context,
 		// Also handles panic recovery
 		r.Use(hlog.NewHandler(*logger))
 		r.Use(hlog.UserAgentHandler("user_agent"))
-		r.Use(hlog.RequestIDHandler("req_id", "Request-Id"))
+		r.Use(hlog.RequestIDHandler("req_id", middleware.RequestIDResponseHeader))
 		r.Use(middleware.RequestLogger(logger))
 	}
 	// now we have middlewares we want included in logging
@@ -409,22 +417,25 @@ func setupRouter(ctx context.Context, logger *zerolog.Logger) (context.Context,
 		}
 	}
 
-	r.Mount("/v1/promotions", promotion.Router(promotionService, vbatExpires))
-	r.Mount("/v2/promotions", promotion.RouterV2(promotionService, vbatExpires))
+	// cors for the public promotion and suggestion routers, denied unless configured outside local
+	publicCORSMwr := newPublicCORSMwr(env, os.Getenv)
+
+	r.Mount("/v1/promotions", publicCORSMwr(promotion.Router(promotionService, vbatExpires)))
+	r.Mount("/v2/promotions", publicCORSMwr(promotion.RouterV2(promotionService, vbatExpires)))
 
 	sRouter, err := promotion.SuggestionsRouter(promotionService, vbatExpires)
 	if err != nil {
 		logger.Panic().Err(err).Msg("failed to initialize the suggestions router")
 	}
 
-	r.Mount("/v1/suggestions", sRouter)
+	r.Mount("/v1/suggestions", publicCORSMwr(sRouter))
 
 	sV2Router, err := promotion.SuggestionsV2Router(promotionService, vbatExpires)
 	if err != nil {
 		logger.Panic().Err(err).Msg("failed to initialize the suggestions router")
 	}
 
-	r.Mount("/v2/suggestions", sV2Router)
+	r.Mount("/v2/suggestions", publicCORSMwr(sV2Router))
 
 	// temporarily house batloss events in promotion to avoid widespread conflicts later
 	r.Mount("/v1/wallets", promotion.WalletEventRouter(promotionService, vbatExpires))
@@ -586,19 +597,11 @@ func GrantServer(
 		ctx, logger = logging.SetupLogger(ctx)
 	}
 
-	sentryDsn := os.Getenv("SENTRY_DSN")
-	if sentryDsn != "" {
-		buildTime := ctx.Value(appctx.BuildTimeCTXKey).(string)
-		commit := ctx.Value(appctx.CommitCTXKey).(string)
-		err := sentry.Init(sentry.ClientOptions{
-			Dsn:     sentryDsn,
-			Release: fmt.Sprintf("bat-go@%s-%s", commit, buildTime),
-		})
-		defer sentry.Flush(2 * time.Second)
-		if err != nil {
-			logger.Panic().Err(err).Msg("unable to setup reporting!")
-		}
+	flushSentry, err := sentryutil.Init(sentryutil.OptionsFromContext(ctx, os.Getenv("SENTRY_DSN"), "grant"))
+	if err != nil {
+		logger.Panic().Err(err).Msg("unable to setup reporting!")
 	}
+	defer flushSentry()
 	logger.Info().
 		Str("prefix", "main").
 		Msg("Starting server")
@@ -685,16 +688,29 @@ func GrantServer(
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	jobMonitor := srv.NewJobMonitor()
 	if enableJobWorkers {
+		// allow ops to tune workers and cadence per job from the environment
+		jobs, err = srv.ApplyJobOverrides(jobs, os.LookupEnv)
+		if err != nil {
+			logger.Panic().Err(err).Msg("invalid job worker overrides")
+		}
 		for _, job := range jobs {
+			name := jobMonitor.Register(job)
+			logger.Info().
+				Str("job", name).
+				Int("workers", job.Workers).
+				Str("cadence", job.Cadence.String()).
+				Msg("job worker settings")
 			// iterate over jobs
 			for i := 0; i < job.Workers; i++ {
 				// spin up a job worker for each worker
-				logger.Debug().Msg("starting job worker")
-				go srv.JobWorker(ctx, job.Func, job.Cadence)
+				logger.Debug().Str("job", name).Msg("starting job worker")
+				go srv.MonitoredJobWorker(ctx, jobMonitor, name, job.Func, job.Cadence)
 			}
 		}
 	}
+	r.Get("/debug/jobs", middleware.SimpleTokenAuthorizedOnly(newJobStatusHandler(jobMonitor)).ServeHTTP)
 
 	go func() {
 		err := http.ListenAndServe(":9090", middleware.Metrics())
@@ -710,11 +726,32 @@ func GrantServer(
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 20 * time.Second,
 	}
+
+	// on termination stop accepting requests, then let in-flight job runs finish before exiting
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		<-sig
+
+		logger.Info().Msg("shutting down server")
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer shutdownCancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Error().Err(err).Msg("failed to gracefully shutdown server")
+		}
+	}()
+
 	err = srv.ListenAndServe()
-	if err != nil {
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		sentry.CaptureException(err)
 		logger.Panic().Err(err).Msg("HTTP server start failed!")
 	}
+
+	drainTimeout := viper.GetDuration("job-drain-timeout")
+	logger.Info().Str("timeout", drainTimeout.String()).Msg("draining job workers")
+	if running := jobMonitor.Drain(drainTimeout); len(running) > 0 {
+		logger.Error().Strs("jobs", running).Msg("job runs still in progress after drain timeout")
+	}
 	return nil
 }
 
@@ -737,3 +774,12 @@ func newSrvStatusFromCtx(ctx context.Context) map[string]any {
 
 	return result
 }
+
+// newJobStatusHandler reports the execution state of each job worker registered with monitor
+func newJobStatusHandler(monitor *srv.JobMonitor) handlers.AppHandler {
+	return func(w http.ResponseWriter, r *http.Request) *handlers.AppError {
+		return handlers.RenderContent(r.Context(), map[string]interface{}{
+			"jobs": monitor.Status(),
+		}, w, http.StatusOK)
+	}
+}
@@ -73,6 +73,9 @@ func blindCredsEq(a, b []string) bool {
 
 var errClaimedDifferentBlindCreds = errors.New("blinded credentials do not match what was already claimed")
 
+// ErrPromotionExhausted is returned when a promotion has no remaining grants to claim
+var ErrPromotionExhausted = errors.New("promotion exhausted")
+
 // ClaimPromotionForWallet attempts to claim the promotion on behalf of a wallet and returning the ClaimID
 // It kicks off asynchronous signing of the credentials on success
 func (service *Service) ClaimPromotionForWallet(
@@ -58,6 +58,7 @@ func Router(service *Service, vbatExpires time.Time) chi.Router {
 
 	r.Method("GET", "/{claimType}/grants/summary", middleware.InstrumentHandler("GetClaimSummary", GetClaimSummary(service)))
 	r.Method("GET", "/", middleware.InstrumentHandler("GetAvailablePromotions", GetAvailablePromotions(service)))
+	r.Method("GET", "/active", middleware.SimpleTokenAuthorizedOnly(middleware.InstrumentHandler("GetActivePromotions", GetActivePromotions(service))))
 	// version 1 clobbered claims
 	r.Method("POST", "/reportclobberedclaims", middleware.InstrumentHandler("ReportClobberedClaims", PostReportClobberedClaims(service, 1)))
 	r.Method("POST", "/{promotionId}", middleware.HTTPSignedOnly(service)(middleware.InstrumentHandler("ClaimPromotion", ClaimPromotion(service))))
@@ -147,6 +148,37 @@ func (service *Service) LookupVerifier(ctx context.Context, keyID string) (conte
 	return ctx, publicKey, nil
 }
 
+// GetActivePromotions is the admin handler for listing the currently active promotions and their remaining budget
+// promotions have no priority, by default they are listed by createdOrder, their position by creation time
+func GetActivePromotions(service *Service) handlers.AppHandler {
+	return handlers.AppHandler(func(w http.ResponseWriter, r *http.Request) *handlers.AppError {
+		// /v1/promotions/active?page=0&items=50&order=createdOrder
+		ctx, pagination, err := inputs.NewPagination(r.Context(), r.URL.String(), new(ActivePromotion))
+		if err != nil {
+			return handlers.WrapValidationError(err)
+		}
+
+		promotions, total, err := service.GetActivePromotions(ctx, pagination)
+		if err != nil {
+			return handlers.WrapError(err, "error getting active promotions", http.StatusInternalServerError)
+		}
+
+		response := &responses.PaginationResponse{
+			Page:    pagination.Page,
+			Items:   pagination.Items,
+			MaxPage: (total+pagination.Items-1)/pagination.Items - 1, // 0 indexed
+			Ordered: pagination.RawOrder,
+			Data:    promotions,
+		}
+
+		if err := response.Render(ctx, w, http.StatusOK); err != nil {
+			return handlers.WrapError(err, "error rendering response", http.StatusInternalServerError)
+		}
+
+		return nil
+	})
+}
+
 // PromotionsResponse is a list of known promotions to be consumed by the browser
 type PromotionsResponse struct {
 	Promotions []Promotion `json:"promotions"`
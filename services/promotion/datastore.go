@@ -17,6 +17,7 @@ import (
 	appctx "github.com/brave-intl/bat-go/libs/context"
 	"github.com/brave-intl/bat-go/libs/datastore"
 	errorutils "github.com/brave-intl/bat-go/libs/errors"
+	"github.com/brave-intl/bat-go/libs/inputs"
 	"github.com/brave-intl/bat-go/libs/jsonutils"
 	walletutils "github.com/brave-intl/bat-go/libs/wallet"
 	"github.com/getsentry/sentry-go"
@@ -89,6 +90,8 @@ type Datastore interface {
 	GetAvailablePromotionsForWallet(wallet *walletutils.Info, platform string) ([]Promotion, error)
 	// GetAvailablePromotions returns the list of available promotions for all wallets
 	GetAvailablePromotions(platform string) ([]Promotion, error)
+	// GetPagedActivePromotions returns a page of the active promotions along with the total number of active promotions
+	GetPagedActivePromotions(ctx context.Context, pagination *inputs.Pagination) (*[]ActivePromotion, int, error)
 	// GetWithdrawalsAssociated returns the promotion and total amount of claims drained for associated wallets
 	GetWithdrawalsAssociated(walletID, claimID *uuid.UUID) (*uuid.UUID, decimal.Decimal, error)
 	// GetPromotionsMissingIssuer returns the list of promotions missing an issuer
@@ -146,6 +149,8 @@ type ReadOnlyDatastore interface {
 	GetWithdrawalsAssociated(walletID, claimID *uuid.UUID) (*uuid.UUID, decimal.Decimal, error)
 	// GetAvailablePromotions returns the list of available promotions for all wallets
 	GetAvailablePromotions(platform string) ([]Promotion, error)
+	// GetPagedActivePromotions returns a page of the active promotions along with the total number of active promotions
+	GetPagedActivePromotions(ctx context.Context, pagination *inputs.Pagination) (*[]ActivePromotion, int, error)
 	// GetPromotionsMissingIssuer returns the list of promotions missing an issuer
 	GetPromotionsMissingIssuer(limit int) ([]uuid.UUID, error)
 	// GetClaimCreds returns the claim credentials for a ClaimID
@@ -512,13 +517,15 @@ func (pg *Postgres) ClaimForWallet(promotion *Promotion, issuer *Issuer, wallet
 	}
 
 	if !legacyClaimExists {
-		// This will error if remaining_grants is insufficient due to constraint or the promotion is inactive
+		// Check and decrement the budget in a single conditional update, concurrent claims
+		// wait on the row lock and re-check remaining_grants so the budget cannot be overdrawn
 		res, err := tx.Exec(`
 			update promotions
 			set remaining_grants = remaining_grants - 1
 			where
 				id = $1 and
 				active and
+				remaining_grants > 0 and
 				promotions.created_at > NOW() - INTERVAL '3 months'`,
 			promotion.ID)
 
@@ -529,6 +536,16 @@ func (pg *Postgres) ClaimForWallet(promotion *Promotion, issuer *Issuer, wallet
 		if err != nil {
 			return nil, err
 		} else if promotionCount != 1 {
+			var exhausted bool
+			err = tx.Get(&exhausted, `
+				select exists(select 1 from promotions where id = $1 and active and remaining_grants = 0)`,
+				promotion.ID)
+			if err != nil {
+				return nil, err
+			}
+			if exhausted {
+				return nil, ErrPromotionExhausted
+			}
 			return nil, errors.New("no matching active promotion")
 		}
 	}
@@ -702,6 +719,56 @@ func (pg *Postgres) GetAvailablePromotions(platform string) ([]Promotion, error)
 	return promotions, nil
 }
 
+// GetPagedActivePromotions returns a page of the active promotions along with the total number of active promotions
+// promotions have no priority, they are ordered by created_order, their position by creation time, unless asked otherwise
+func (pg *Postgres) GetPagedActivePromotions(ctx context.Context, pagination *inputs.Pagination) (*[]ActivePromotion, int, error) {
+	var total int
+	countStatement := `
+		select count(*) from promotions
+		where active and expires_at > now()`
+	if err := pg.RawDB().GetContext(ctx, &total, countStatement); err != nil {
+		return nil, 0, err
+	}
+
+	statement := `
+		select * from (
+			select
+				id,
+				promotion_type,
+				platform,
+				approximate_value,
+				suggestions_per_grant,
+				remaining_grants,
+				created_at,
+				expires_at,
+				row_number() over (order by created_at) as created_order
+			from promotions
+			where active and expires_at > now()
+		) as active_promotions`
+
+	orderBy := pagination.GetOrderBy(ctx)
+	if orderBy == "" {
+		orderBy = "created_order"
+	}
+	statement += fmt.Sprintf(" ORDER BY %s", orderBy)
+
+	offset := pagination.Page * pagination.Items
+	if offset > 0 {
+		statement += fmt.Sprintf(" OFFSET %d", offset)
+	}
+
+	if pagination.Items > 0 {
+		statement += fmt.Sprintf(" FETCH NEXT %d ROWS ONLY", pagination.Items)
+	}
+
+	promotions := []ActivePromotion{}
+	if err := pg.RawDB().SelectContext(ctx, &promotions, statement); err != nil {
+		return nil, 0, err
+	}
+
+	return &promotions, total, nil
+}
+
 // GetPromotionsMissingIssuer returns the list of promotions missing an issuer
 func (pg *Postgres) GetPromotionsMissingIssuer(limit int) ([]uuid.UUID, error) {
 	var (
@@ -5,7 +5,9 @@ package promotion
 import (
 	"context"
 	"errors"
+	"sync"
 
+	"github.com/brave-intl/bat-go/libs/inputs"
 	"github.com/brave-intl/bat-go/libs/jsonutils"
 	walletutils "github.com/brave-intl/bat-go/libs/wallet"
 	"github.com/brave-intl/bat-go/services/wallet"
@@ -224,7 +226,7 @@ func (suite *PostgresTestSuite) TestClaimForWallet() {
 	w = &walletutils.Info{ID: uuid.NewV4().String(), Provider: "uphold", ProviderID: uuid.NewV4().String(), PublicKey: publicKey}
 	suite.Require().NoError(walletDB.UpsertWallet(context.Background(), w), "Save wallet should succeed")
 	_, err = pg.ClaimForWallet(promotion, issuer, w, blindedCreds)
-	suite.Require().Error(err, "Claim for wallet should fail, promotion is active but has no more grants")
+	suite.Require().ErrorIs(err, ErrPromotionExhausted, "Claim for wallet should fail, promotion is active but has no more grants")
 
 	promotion, err = pg.CreatePromotion("ads", 2, decimal.NewFromFloat(25.0), "")
 	suite.Require().NoError(err, "Create promotion should succeed")
@@ -249,6 +251,65 @@ func (suite *PostgresTestSuite) TestClaimForWallet() {
 	suite.Assert().Equal(1, promotion.RemainingGrants)
 }
 
+func (suite *PostgresTestSuite) TestClaimForWallet_ConcurrentClaimsDoNotOverdraw() {
+	pg, _, err := NewPostgres()
+	suite.Require().NoError(err)
+
+	walletDB, _, err := wallet.NewPostgres()
+	suite.Require().NoError(err)
+
+	const (
+		budget = 3
+		claims = 12
+	)
+
+	publicKey := "hBrtClwIppLmu/qZ8EhGM1TQZUwDUosbOrVu3jMwryY="
+	blindedCreds := jsonutils.JSONStringArray([]string{})
+
+	promotion, err := pg.CreatePromotion("ugp", budget, decimal.NewFromFloat(25.0), "")
+	suite.Require().NoError(err, "Create promotion should succeed")
+	suite.Require().NoError(pg.ActivatePromotion(promotion), "Activate promotion should succeed")
+
+	issuer := &Issuer{PromotionID: promotion.ID, Cohort: "control", PublicKey: publicKey}
+	issuer, err = pg.InsertIssuer(issuer)
+	suite.Require().NoError(err, "Insert issuer should succeed")
+
+	wallets := make([]*walletutils.Info, claims)
+	for i := range wallets {
+		wallets[i] = &walletutils.Info{ID: uuid.NewV4().String(), Provider: "uphold", ProviderID: uuid.NewV4().String(), PublicKey: publicKey}
+		suite.Require().NoError(walletDB.UpsertWallet(context.Background(), wallets[i]), "Save wallet should succeed")
+	}
+
+	var (
+		wg   sync.WaitGroup
+		errs = make(chan error, claims)
+	)
+	for _, w := range wallets {
+		wg.Add(1)
+		go func(w *walletutils.Info) {
+			defer wg.Done()
+			_, err := pg.ClaimForWallet(promotion, issuer, w, blindedCreds)
+			errs <- err
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		suite.Assert().ErrorIs(err, ErrPromotionExhausted)
+	}
+	suite.Assert().Equal(budget, succeeded)
+
+	promotion, err = pg.GetPromotion(promotion.ID)
+	suite.Require().NoError(err, "Get promotion should succeed")
+	suite.Assert().Equal(0, promotion.RemainingGrants)
+}
+
 func (suite *PostgresTestSuite) TestGetAvailablePromotionsForWallet() {
 	pg, _, err := NewPostgres()
 	suite.Require().NoError(err)
@@ -343,6 +404,49 @@ func (suite *PostgresTestSuite) TestGetAvailablePromotionsForWallet() {
 	suite.Assert().Equal(1, adSuggestionsPerGrant)
 }
 
+func (suite *PostgresTestSuite) TestGetPagedActivePromotions() {
+	pg, _, err := NewPostgres()
+	suite.Require().NoError(err)
+
+	ctx := context.Background()
+	pagination := &inputs.Pagination{Items: 1}
+
+	promotions, total, err := pg.GetPagedActivePromotions(ctx, pagination)
+	suite.Require().NoError(err, "Get active promotions should succeed")
+	suite.Assert().Equal(0, total)
+	suite.Assert().Empty(*promotions)
+
+	first, err := pg.CreatePromotion("ugp", 2, decimal.NewFromFloat(25.0), "")
+	suite.Require().NoError(err, "Create promotion should succeed")
+	suite.Require().NoError(pg.ActivatePromotion(first), "Activate promotion should succeed")
+
+	second, err := pg.CreatePromotion("ads", 0, decimal.NewFromFloat(15.0), "")
+	suite.Require().NoError(err, "Create promotion should succeed")
+	suite.Require().NoError(pg.ActivatePromotion(second), "Activate promotion should succeed")
+
+	// inactive promotions are not listed
+	_, err = pg.CreatePromotion("ugp", 2, decimal.NewFromFloat(25.0), "")
+	suite.Require().NoError(err, "Create promotion should succeed")
+
+	promotions, total, err = pg.GetPagedActivePromotions(ctx, pagination)
+	suite.Require().NoError(err, "Get active promotions should succeed")
+	suite.Assert().Equal(2, total)
+	suite.Require().Len(*promotions, 1)
+	suite.Assert().Equal(first.ID, (*promotions)[0].ID)
+	suite.Assert().Equal(1, (*promotions)[0].CreatedOrder)
+	suite.Assert().Equal(2, (*promotions)[0].RemainingGrants)
+
+	// promotions with no remaining grants are still active
+	pagination.Page = 1
+	promotions, _, err = pg.GetPagedActivePromotions(ctx, pagination)
+	suite.Require().NoError(err, "Get active promotions should succeed")
+	suite.Require().Len(*promotions, 1)
+	suite.Assert().Equal(second.ID, (*promotions)[0].ID)
+	suite.Assert().Equal("ads", (*promotions)[0].Type)
+	suite.Assert().Equal(2, (*promotions)[0].CreatedOrder)
+	suite.Assert().Equal(0, (*promotions)[0].RemainingGrants)
+}
+
 func (suite *PostgresTestSuite) TestGetAvailablePromotions() {
 	pg, _, err := NewPostgres()
 	suite.Require().NoError(err)
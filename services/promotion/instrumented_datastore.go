@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/brave-intl/bat-go/libs/clients/cbr"
+	"github.com/brave-intl/bat-go/libs/inputs"
 	"github.com/brave-intl/bat-go/libs/jsonutils"
 	walletutils "github.com/brave-intl/bat-go/libs/wallet"
 	migrate "github.com/golang-migrate/migrate/v4"
@@ -255,6 +256,20 @@ func (_d DatastoreWithPrometheus) GetOrder(orderID uuid.UUID) (op1 *Order, err e
 	return _d.base.GetOrder(orderID)
 }
 
+// GetPagedActivePromotions implements Datastore
+func (_d DatastoreWithPrometheus) GetPagedActivePromotions(ctx context.Context, pagination *inputs.Pagination) (apap1 *[]ActivePromotion, i1 int, err error) {
+	_since := time.Now()
+	defer func() {
+		result := "ok"
+		if err != nil {
+			result = "error"
+		}
+
+		datastoreDurationSummaryVec.WithLabelValues(_d.instanceName, "GetPagedActivePromotions", result).Observe(time.Since(_since).Seconds())
+	}()
+	return _d.base.GetPagedActivePromotions(ctx, pagination)
+}
+
 // GetPreClaim implements Datastore
 func (_d DatastoreWithPrometheus) GetPreClaim(promotionID uuid.UUID, walletID string) (cp1 *Claim, err error) {
 	_since := time.Now()
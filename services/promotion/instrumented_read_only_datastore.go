@@ -7,8 +7,10 @@ package promotion
 //go:generate gowrap gen -p github.com/brave-intl/bat-go/services/promotion -i ReadOnlyDatastore -t ../../.prom-gowrap.tmpl -o instrumented_read_only_datastore.go -l ""
 
 import (
+	"context"
 	"time"
 
+	"github.com/brave-intl/bat-go/libs/inputs"
 	walletutils "github.com/brave-intl/bat-go/libs/wallet"
 	migrate "github.com/golang-migrate/migrate/v4"
 	"github.com/jmoiron/sqlx"
@@ -154,6 +156,20 @@ func (_d ReadOnlyDatastoreWithPrometheus) GetIssuerByPublicKey(publicKey string)
 	return _d.base.GetIssuerByPublicKey(publicKey)
 }
 
+// GetPagedActivePromotions implements ReadOnlyDatastore
+func (_d ReadOnlyDatastoreWithPrometheus) GetPagedActivePromotions(ctx context.Context, pagination *inputs.Pagination) (apap1 *[]ActivePromotion, i1 int, err error) {
+	_since := time.Now()
+	defer func() {
+		result := "ok"
+		if err != nil {
+			result = "error"
+		}
+
+		readonlydatastoreDurationSummaryVec.WithLabelValues(_d.instanceName, "GetPagedActivePromotions", result).Observe(time.Since(_since).Seconds())
+	}()
+	return _d.base.GetPagedActivePromotions(ctx, pagination)
+}
+
 // GetPreClaim implements ReadOnlyDatastore
 func (_d ReadOnlyDatastoreWithPrometheus) GetPreClaim(promotionID uuid.UUID, walletID string) (cp1 *Claim, err error) {
 	_since := time.Now()
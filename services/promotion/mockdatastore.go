@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	cbr "github.com/brave-intl/bat-go/libs/clients/cbr"
+	inputs "github.com/brave-intl/bat-go/libs/inputs"
 	jsonutils "github.com/brave-intl/bat-go/libs/jsonutils"
 	wallet "github.com/brave-intl/bat-go/libs/wallet"
 	v4 "github.com/golang-migrate/migrate/v4"
@@ -264,6 +265,22 @@ func (mr *MockDatastoreMockRecorder) GetOrder(orderID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockDatastore)(nil).GetOrder), orderID)
 }
 
+// GetPagedActivePromotions mocks base method.
+func (m *MockDatastore) GetPagedActivePromotions(ctx context.Context, pagination *inputs.Pagination) (*[]ActivePromotion, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPagedActivePromotions", ctx, pagination)
+	ret0, _ := ret[0].(*[]ActivePromotion)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPagedActivePromotions indicates an expected call of GetPagedActivePromotions.
+func (mr *MockDatastoreMockRecorder) GetPagedActivePromotions(ctx, pagination interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPagedActivePromotions", reflect.TypeOf((*MockDatastore)(nil).GetPagedActivePromotions), ctx, pagination)
+}
+
 // GetPreClaim mocks base method.
 func (m *MockDatastore) GetPreClaim(promotionID go_uuid.UUID, walletID string) (*Claim, error) {
 	m.ctrl.T.Helper()
@@ -687,6 +704,22 @@ func (mr *MockReadOnlyDatastoreMockRecorder) GetIssuerByPublicKey(publicKey inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssuerByPublicKey", reflect.TypeOf((*MockReadOnlyDatastore)(nil).GetIssuerByPublicKey), publicKey)
 }
 
+// GetPagedActivePromotions mocks base method.
+func (m *MockReadOnlyDatastore) GetPagedActivePromotions(ctx context.Context, pagination *inputs.Pagination) (*[]ActivePromotion, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPagedActivePromotions", ctx, pagination)
+	ret0, _ := ret[0].(*[]ActivePromotion)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPagedActivePromotions indicates an expected call of GetPagedActivePromotions.
+func (mr *MockReadOnlyDatastoreMockRecorder) GetPagedActivePromotions(ctx, pagination interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPagedActivePromotions", reflect.TypeOf((*MockReadOnlyDatastore)(nil).GetPagedActivePromotions), ctx, pagination)
+}
+
 // GetPreClaim mocks base method.
 func (m *MockReadOnlyDatastore) GetPreClaim(promotionID go_uuid.UUID, walletID string) (*Claim, error) {
 	m.ctrl.T.Helper()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/brave-intl/bat-go/libs/inputs"
 	"github.com/brave-intl/bat-go/libs/jsonutils"
 	"github.com/brave-intl/bat-go/libs/logging"
 	"github.com/prometheus/client_golang/prometheus"
@@ -64,6 +65,20 @@ type Promotion struct {
 	ClaimableUntilOverride *time.Time `json:"-" db:"claimable_until_override"`
 }
 
+// ActivePromotion is an operational view of an active promotion and its remaining budget
+// promotions have no priority, CreatedOrder is the 1 indexed position of the promotion by creation time
+type ActivePromotion struct {
+	ID                  uuid.UUID       `json:"id" db:"id"`
+	CreatedOrder        int             `json:"createdOrder" db:"created_order"`
+	Type                string          `json:"type" db:"promotion_type"`
+	Platform            string          `json:"platform" db:"platform"`
+	ApproximateValue    decimal.Decimal `json:"approximateValue" db:"approximate_value"`
+	SuggestionsPerGrant int             `json:"suggestionsPerGrant" db:"suggestions_per_grant"`
+	RemainingGrants     int             `json:"remainingGrants" db:"remaining_grants"`
+	CreatedAt           time.Time       `json:"createdAt" db:"created_at"`
+	ExpiresAt           time.Time       `json:"expiresAt" db:"expires_at"`
+}
+
 // Filter promotions to all that satisfy the function passed
 func Filter(orig []Promotion, f func(Promotion) bool) []Promotion {
 	promos := make([]Promotion, 0)
@@ -143,3 +158,8 @@ func (service *Service) GetAvailablePromotions(
 	promos, err := service.ReadableDatastore().GetAvailablePromotions(platform)
 	return &promos, err
 }
+
+// GetActivePromotions retrieves a page of the currently active promotions along with the total number of active promotions
+func (service *Service) GetActivePromotions(ctx context.Context, pagination *inputs.Pagination) (*[]ActivePromotion, int, error) {
+	return service.ReadableDatastore().GetPagedActivePromotions(ctx, pagination)
+}
@@ -99,6 +99,7 @@ func InitService(ctx context.Context) (context.Context, *Service, error) {
 
 	service.jobs = []srv.Job{
 		{
+			Name:    "relative-cache-prepopulation",
 			Func:    service.RunNextRelativeCachePrepopulationJob,
 			Cadence: 5 * time.Minute,
 			Workers: 1,
@@ -393,11 +393,13 @@ func InitService(
 
 	service.jobs = []srv.Job{
 		{
+			Name:    "vote-drain",
 			Func:    service.RunNextVoteDrainJob,
 			Cadence: 2 * time.Second,
 			Workers: 1,
 		},
 		{
+			Name:    "send-signing-request",
 			Func:    service.RunSendSigningRequestJob,
 			Cadence: 100 * time.Millisecond,
 			Workers: 1,
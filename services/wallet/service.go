@@ -304,11 +304,13 @@ func SetupService(ctx context.Context) (context.Context, *Service) {
 
 	s.jobs = []srv.Job{
 		{
+			Name:    "refresh-custodian-regions",
 			Func:    s.RefreshCustodianRegionsWorker,
 			Cadence: 15 * time.Minute,
 			Workers: 1,
 		},
 		{
+			Name:    "delete-expired-challenges",
 			Func:    decJob.deleteExpiredChallenges,
 			Cadence: 10 * time.Minute,
 			Workers: 1,
@@ -317,6 +319,7 @@ func SetupService(ctx context.Context) (context.Context, *Service) {
 
 	if VerifiedWalletEnable {
 		s.jobs = append(s.jobs, srv.Job{
+			Name:    "verified-wallet",
 			Func:    s.RunVerifiedWalletWorker,
 			Cadence: 1 * time.Second,
 			Workers: 1,
@@ -15,6 +15,7 @@ import (
 	rootcmd "github.com/brave-intl/bat-go/cmd"
 	"github.com/brave-intl/bat-go/libs/custodian"
 
+	"github.com/brave-intl/bat-go/libs/clients/ratesclient"
 	"github.com/brave-intl/bat-go/libs/closers"
 	"github.com/brave-intl/bat-go/tools/settlement"
 	"github.com/brave-intl/bat-go/tools/settlement/paypal"
@@ -52,7 +53,8 @@ func init() {
 		Require()
 
 	transformBuilder.Flag().String("currency", "",
-		"a currency must be set (usually JPY)").
+		"a currency or comma delimited list of currencies must be set (usually JPY), "+
+			"with a list payouts are split by their currency into one file per currency").
 		Env("CURRENCY").
 		Bind("currency").
 		Require()
@@ -67,6 +69,26 @@ func init() {
 		"a currency must be set (usually JPY)").
 		Bind("rate").
 		Env("RATE")
+
+	transformBuilder.Flag().Float64("max-payment", 0,
+		"the largest single payment in the settlement currency, 0 uses paypal's limit for the currency").
+		Bind("max-payment").
+		Env("MAX_PAYMENT")
+
+	transformBuilder.Flag().Float64("max-total", 0,
+		"the largest total of the mass pay file in the settlement currency, 0 is uncapped").
+		Bind("max-total").
+		Env("MAX_TOTAL")
+
+	transformBuilder.Flag().Duration("rate-cache-ttl", time.Minute,
+		"how long a rate fetched from ratios is reused, 0 disables the cache").
+		Bind("rate-cache-ttl").
+		Env("RATE_CACHE_TTL")
+
+	transformBuilder.Flag().Duration("rate-cache-stale", 0,
+		"how long after its ttl a cached rate may still be used while it is refreshed").
+		Bind("rate-cache-stale").
+		Env("RATE_CACHE_STALE")
 }
 
 // PaypalEmailTemplate performs template replacement of date fields in emails
@@ -174,14 +196,84 @@ func RunTransformPaypalSettlement(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	maxPayment, err := cmd.Flags().GetFloat64("max-payment")
+	if err != nil {
+		return err
+	}
+	maxTotal, err := cmd.Flags().GetFloat64("max-total")
+	if err != nil {
+		return err
+	}
+	var cache paypal.RateCacheConfig
+	cache.TTL, err = cmd.Flags().GetDuration("rate-cache-ttl")
+	if err != nil {
+		return err
+	}
+	cache.Stale, err = cmd.Flags().GetDuration("rate-cache-stale")
+	if err != nil {
+		return err
+	}
 
-	return PaypalTransformForMassPay(
-		cmd.Context(),
-		payouts,
-		currency,
-		decimal.NewFromFloat(rate),
-		out,
-	)
+	// rates are only fetched when none is given, a single client keeps its cache across currencies
+	var rates ratesclient.Client
+	if rate == 0 {
+		rates, err = paypal.NewRatesClient(cmd.Context(), cache)
+		if err != nil {
+			return err
+		}
+	}
+
+	currencies := strings.Split(currency, ",")
+	for i := range currencies {
+		currencies[i] = strings.TrimSpace(currencies[i])
+	}
+	if len(currencies) == 1 {
+		limits := paypal.DefaultLimits(currency)
+		if maxPayment > 0 {
+			limits.MaxPayment = decimal.NewFromFloat(maxPayment)
+		}
+		if maxTotal > 0 {
+			limits.MaxTotal = decimal.NewFromFloat(maxTotal)
+		}
+
+		return PaypalTransformForMassPay(
+			cmd.Context(),
+			payouts,
+			rates,
+			currency,
+			decimal.NewFromFloat(rate),
+			out,
+			limits,
+		)
+	}
+
+	// a single rate or limit can not apply to every currency, rates are fetched for each currency
+	if rate != 0 || maxPayment != 0 || maxTotal != 0 {
+		return errors.New("--rate, --max-payment and --max-total can only be used with a single currency")
+	}
+	payoutsByCurrency, err := paypal.SplitByCurrency(*payouts, currencies)
+	if err != nil {
+		return err
+	}
+	for _, currency := range currencies {
+		currencyPayouts := payoutsByCurrency[currency]
+		if len(currencyPayouts) == 0 {
+			continue
+		}
+		err = PaypalTransformForMassPay(
+			cmd.Context(),
+			&currencyPayouts,
+			rates,
+			currency,
+			decimal.Zero,
+			out+"-"+currency,
+			paypal.DefaultLimits(currency),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to transform %s payouts: %w", currency, err)
+		}
+	}
+	return nil
 }
 
 // CompletePaypalSettlement added complete paypal settlement
@@ -267,9 +359,6 @@ func PaypalWriteMassPayCSV(ctx context.Context, outPath string, metadata *[]payp
 		currency = row.Currency
 		rows = append(rows, row)
 	}
-	if len(rows) > 5000 {
-		return errors.New("a payout cannot be larger than 5000 lines items long")
-	}
 	logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
 		return c.Int("payouts", len(rows)).
 			Str("total", total.String()).
@@ -293,24 +382,33 @@ func PaypalWriteMassPayCSV(ctx context.Context, outPath string, metadata *[]payp
 	return nil
 }
 
-// PaypalTransformForMassPay starts the process to transform a settlement into a mass pay csv
-func PaypalTransformForMassPay(ctx context.Context, payouts *[]custodian.Transaction, currency string, rate decimal.Decimal, out string) error {
-	rate, err := paypal.GetRate(ctx, currency, rate)
+// PaypalTransformForMassPay starts the process to transform a settlement into a mass pay csv, the payouts
+// are checked against the limits before anything is written. A zero rate is fetched from rates
+func PaypalTransformForMassPay(
+	ctx context.Context,
+	payouts *[]custodian.Transaction,
+	rates ratesclient.Client,
+	currency string,
+	rate decimal.Decimal,
+	out string,
+	limits paypal.Limits,
+) error {
+	rate, err := paypal.GetRate(ctx, rates, currency, rate)
 	if err != nil {
 		return err
 	}
 
-	txs, err := paypal.CalculateTransactionAmounts(currency, rate, payouts)
+	txs, err := paypal.CalculateTransactionAmounts(currency, rate, payouts, limits)
 	if err != nil {
 		return err
 	}
 
-	err = PaypalWriteTransactions(out+".json", txs)
+	metadata, err := paypal.MergeAndTransformPayouts(txs, limits)
 	if err != nil {
 		return err
 	}
 
-	metadata, err := paypal.MergeAndTransformPayouts(txs)
+	err = PaypalWriteTransactions(out+".json", txs)
 	if err != nil {
 		return err
 	}
@@ -4,21 +4,31 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	cmdutils "github.com/brave-intl/bat-go/cmd"
 	rootcmd "github.com/brave-intl/bat-go/cmd"
+	"github.com/brave-intl/bat-go/libs/backoff"
+	"github.com/brave-intl/bat-go/libs/backoff/retrypolicy"
+	"github.com/brave-intl/bat-go/libs/closers"
 	appctx "github.com/brave-intl/bat-go/libs/context"
 	"github.com/brave-intl/bat-go/libs/custodian"
+	errorutils "github.com/brave-intl/bat-go/libs/errors"
 	"github.com/brave-intl/bat-go/libs/logging"
+	"github.com/brave-intl/bat-go/libs/sentryutil"
 	"github.com/brave-intl/bat-go/libs/wallet/provider/uphold"
 	"github.com/brave-intl/bat-go/tools/settlement"
+	upholdsettlement "github.com/brave-intl/bat-go/tools/settlement/uphold"
+	"github.com/gocarina/gocsv"
+	"github.com/shopspring/decimal"
 	"github.com/spf13/cobra"
 )
 
@@ -34,11 +44,18 @@ var (
 		Short: "upload to uphold",
 		Run:   rootcmd.Perform("upload", RunUpholdUpload),
 	}
+	// UpholdReconcileCmd uphold reconcile subcommand
+	UpholdReconcileCmd = &cobra.Command{
+		Use:   "reconcile",
+		Short: "reconcile an uploaded settlement against the transactions recorded by uphold",
+		Run:   rootcmd.Perform("reconcile", RunUpholdReconcile),
+	}
 )
 
 func init() {
 	UpholdCmd.AddCommand(
 		UpholdUploadCmd,
+		UpholdReconcileCmd,
 	)
 
 	SettlementCmd.AddCommand(
@@ -59,6 +76,29 @@ func init() {
 	uploadBuilder.Flag().String("progress", "1s",
 		"how often progress should be printed out").
 		Bind("progress")
+
+	uploadBuilder.Flag().Int("max-retries", 5,
+		"how many times to retry submitting a transaction after a transient uphold error, such as a 5xx response").
+		Bind("max-retries")
+
+	uploadBuilder.Flag().Bool("check-destinations", false,
+		"also refuse settlements which pay different channels to the same destination").
+		Bind("check-destinations")
+
+	uploadBuilder.Flag().Bool("dry-run", false,
+		"check the input and transaction log and report what would be submitted without calling uphold").
+		Bind("dry-run")
+
+	reconcileBuilder := cmdutils.NewFlagBuilder(UpholdReconcileCmd)
+
+	reconcileBuilder.Flag().String("input", "",
+		"input file that was submitted with upload, its transaction log is read alongside it").
+		Bind("input").
+		Require()
+
+	reconcileBuilder.Flag().String("out", "",
+		"the file to write the reconciliation report to, defaults to the input file with a -reconciliation.csv suffix").
+		Bind("out")
 }
 
 // RunUpholdUpload the runner that the uphold upload command calls
@@ -76,6 +116,42 @@ func RunUpholdUpload(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return err
+	}
+	maxRetries, err := cmd.Flags().GetInt("max-retries")
+	if err != nil {
+		return err
+	}
+	if maxRetries < 0 {
+		return errors.New("max-retries must not be negative")
+	}
+	checkDestinations, err := cmd.Flags().GetBool("check-destinations")
+	if err != nil {
+		return err
+	}
+
+	logFile := strings.TrimSuffix(inputFile, filepath.Ext(inputFile)) + "-log.json"
+	outputFilePrefix := strings.TrimSuffix(inputFile, filepath.Ext(inputFile))
+
+	if dryRun {
+		return UpholdUploadDryRun(
+			ctx,
+			inputFile,
+			logFile,
+			outputFilePrefix+"-dry-run.json",
+			checkDestinations,
+		)
+	}
+
+	// report unanticipated errors during submission to sentry
+	flushSentry, err := sentryutil.Init(sentryutil.OptionsFromContext(ctx, os.Getenv("SENTRY_DSN"), "settlement-uphold-upload"))
+	if err != nil {
+		return err
+	}
+	defer flushSentry()
+
 	// setup context for logging, debug and progress
 	ctx = context.WithValue(ctx, appctx.DebugLoggingCTXKey, verbose)
 
@@ -87,17 +163,114 @@ func RunUpholdUpload(cmd *cobra.Command, args []string) error {
 	progChan := logging.UpholdReportProgress(ctx, progressDuration)
 	ctx = context.WithValue(ctx, appctx.ProgressLoggingCTXKey, progChan)
 
-	logFile := strings.TrimSuffix(inputFile, filepath.Ext(inputFile)) + "-log.json"
-	outputFilePrefix := strings.TrimSuffix(inputFile, filepath.Ext(inputFile))
-
 	return UpholdUpload(
 		ctx,
 		inputFile,
 		logFile,
 		outputFilePrefix,
+		maxRetries,
+		checkDestinations,
 	)
 }
 
+// RunUpholdReconcile the runner that the uphold reconcile command calls
+func RunUpholdReconcile(cmd *cobra.Command, args []string) error {
+	inputFile, err := cmd.Flags().GetString("input")
+	if err != nil {
+		return err
+	}
+	out, err := cmd.Flags().GetString("out")
+	if err != nil {
+		return err
+	}
+
+	prefix := strings.TrimSuffix(inputFile, filepath.Ext(inputFile))
+	if out == "" {
+		out = prefix + "-reconciliation.csv"
+	}
+
+	return UpholdReconcile(cmd.Context(), inputFile, prefix+"-log.json", out)
+}
+
+// UpholdReconcile compares the latest state of each transaction in the upload log with the
+// transaction uphold recorded for it and writes a csv report of every transaction
+func UpholdReconcile(
+	ctx context.Context,
+	inputFile string,
+	logFile string,
+	outputFile string,
+) error {
+	logger, err := appctx.GetLogger(ctx)
+	if err != nil {
+		_, logger = logging.SetupLogger(ctx)
+	}
+
+	settlementJSON, err := ioutil.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read input file: %w", err)
+	}
+	var settlementState settlement.State
+	if err := json.Unmarshal(settlementJSON, &settlementState); err != nil {
+		return fmt.Errorf("failed to unmarshal input file: %w", err)
+	}
+
+	// the log holds the most recent state of each transaction, keyed by channel as in upload
+	f, err := os.Open(logFile)
+	if err != nil {
+		return fmt.Errorf("failed to open transaction log: %w", err)
+	}
+	defer closers.Panic(ctx, f)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var tmp custodian.Transaction
+		if err := json.Unmarshal(scanner.Bytes(), &tmp); err != nil {
+			return fmt.Errorf("failed to scan the transaction log: %w", err)
+		}
+		for i := 0; i < len(settlementState.Transactions); i++ {
+			if settlementState.Transactions[i].Channel == tmp.Channel {
+				settlementState.Transactions[i] = tmp
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to scan the transaction log: %w", err)
+	}
+
+	settlementWallet, err := uphold.FromWalletInfo(ctx, settlementState.WalletInfo)
+	if err != nil {
+		return fmt.Errorf("failed to make settlement wallet: %w", err)
+	}
+
+	rows := upholdsettlement.Reconcile(ctx, settlementWallet, settlementState.Transactions)
+
+	discrepancies := 0
+	for _, row := range rows {
+		if row.HasDiscrepancy() {
+			discrepancies++
+		}
+	}
+
+	data, err := gocsv.MarshalString(&rows)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(outputFile, []byte(data), 0600); err != nil {
+		return err
+	}
+
+	logger.Info().
+		Int("transactions", len(rows)).
+		Int("discrepancies", discrepancies).
+		Str("out", outputFile).
+		Msg("reconciliation report written")
+
+	if discrepancies > 0 {
+		return fmt.Errorf("%d of %d transactions do not match uphold, see %s", discrepancies, len(rows), outputFile)
+	}
+	return nil
+}
+
 func recordProgress(f *os.File, settlementTransaction *custodian.Transaction) error {
 	var out []byte
 	out, err := json.Marshal(settlementTransaction)
@@ -117,12 +290,181 @@ func recordProgress(f *os.File, settlementTransaction *custodian.Transaction) er
 	return nil
 }
 
+// replayTransactionLog applies the transactions recorded in the log to the settlement transactions, returning
+// whether any transaction had already been submitted
+func replayTransactionLog(r io.Reader, transactions []custodian.Transaction) (bool, error) {
+	scanner := bufio.NewScanner(r)
+	isResubmit := false
+	for scanner.Scan() {
+		var tmp custodian.Transaction
+		err := json.Unmarshal(scanner.Bytes(), &tmp)
+		if err != nil {
+			return isResubmit, err
+		}
+		for i := 0; i < len(transactions); i++ {
+			// Only one transaction per channel is allowed per settlement
+			if transactions[i].Channel == tmp.Channel {
+				isResubmit = true
+				transactions[i] = tmp
+			}
+		}
+	}
+	return isResubmit, scanner.Err()
+}
+
+// UpholdDryRunTransaction describes what an uphold upload would do with a settlement transaction
+type UpholdDryRunTransaction struct {
+	Channel     string          `json:"channel"`
+	Probi       decimal.Decimal `json:"probi"`
+	Destination string          `json:"destination"`
+	Status      string          `json:"status,omitempty"`
+	// Action is submit for transactions which would be submitted, otherwise skip
+	Action string `json:"action"`
+	// Problem describes why the transaction can not be submitted
+	Problem string `json:"problem,omitempty"`
+}
+
+// UpholdUploadDryRun checks the settlement and its transaction log as an upload would, writing the transactions
+// which would be submitted to the report file without calling uphold. An error is returned if the upload would
+// not be able to submit every transaction
+func UpholdUploadDryRun(
+	ctx context.Context,
+	inputFile string,
+	logFile string,
+	reportFile string,
+	checkDestinations bool,
+) error {
+	logger, err := appctx.GetLogger(ctx)
+	if err != nil {
+		_, logger = logging.SetupLogger(ctx)
+	}
+	logger.Info().Msg("beginning uphold upload dry run")
+
+	settlementJSON, err := ioutil.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read input file: %w", err)
+	}
+
+	var settlementState settlement.State
+	err = json.Unmarshal(settlementJSON, &settlementState)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal input file: %w", err)
+	}
+
+	if settlementState.WalletInfo.ProviderID == "" {
+		return errors.New("input file is missing the settlement wallet")
+	}
+
+	err = settlement.CheckForDuplicateTransactions(settlementState.Transactions, checkDestinations)
+	if err != nil {
+		return err
+	}
+
+	// the log is only read, a dry run must leave it as the next upload expects to find it
+	f, err := os.Open(logFile)
+	if err == nil {
+		defer closers.Panic(ctx, f)
+		logger.Info().Msg("scanning stateful logs to establish transaction status")
+		_, err = replayTransactionLog(f, settlementState.Transactions)
+		if err != nil {
+			return fmt.Errorf("failed to scan the transaction log: %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to open the transaction log: %w", err)
+	}
+
+	report := make([]UpholdDryRunTransaction, 0, len(settlementState.Transactions))
+	problems := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTION\tCHANNEL\tPROBI\tDESTINATION\tPROBLEM")
+	for _, tx := range settlementState.Transactions {
+		planned := UpholdDryRunTransaction{
+			Channel:     tx.Channel,
+			Probi:       tx.Probi,
+			Destination: tx.Destination,
+			Status:      tx.Status,
+			Action:      "submit",
+		}
+		switch {
+		case tx.IsComplete() || tx.IsFailed():
+			planned.Action = "skip"
+		case tx.SignedTx == "":
+			planned.Problem = "transaction is not signed"
+		case tx.Destination == "":
+			planned.Problem = "transaction has no destination"
+		case !tx.Probi.IsPositive():
+			planned.Problem = "transaction probi is not greater than 0"
+		}
+		if planned.Problem != "" {
+			problems++
+		}
+		report = append(report, planned)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			planned.Action, planned.Channel, planned.Probi, planned.Destination, planned.Problem)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(report, "", "    ")
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(reportFile, out, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write dry run report: %w", err)
+	}
+
+	logger.Info().
+		Str("report", reportFile).
+		Int("transactions", len(report)).
+		Int("problems", problems).
+		Msg("dry run complete, nothing was submitted")
+
+	if problems > 0 {
+		return fmt.Errorf("%d transactions can not be submitted", problems)
+	}
+	return nil
+}
+
+// submitWithRetry submits a prepared transaction, retrying with exponential backoff while uphold returns
+// transient errors. Submitting does not move funds until the transaction is confirmed so it is safe to retry
+func submitWithRetry(
+	ctx context.Context,
+	settlementWallet *uphold.Wallet,
+	settlementTransaction *custodian.Transaction,
+	maxRetries int,
+) error {
+	retryPolicy, err := retrypolicy.New(
+		retrypolicy.WithInitialInterval(time.Second),
+		retrypolicy.WithBackoffCoefficient(2.0),
+		retrypolicy.WithMaximumInterval(time.Minute),
+		retrypolicy.WithExpirationInterval(15*time.Minute),
+		retrypolicy.WithMaximumAttempts(maxRetries),
+	)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.Logger(ctx, "settlement.submitWithRetry")
+	_, err = backoff.Retry(ctx, func() (interface{}, error) {
+		err := settlement.SubmitPreparedTransaction(ctx, settlementWallet, settlementTransaction)
+		if errorutils.IsErrTransient(err) {
+			logger.Warn().Err(err).Str("channel", settlementTransaction.Channel).Msg("transient error submitting, retrying")
+		}
+		return nil, err
+	}, retryPolicy, errorutils.IsErrTransient)
+	return err
+}
+
 // UpholdUpload uploads transactions to uphold
 func UpholdUpload(
 	ctx context.Context,
 	inputFile string,
 	logFile string,
 	outputFilePrefix string,
+	maxRetries int,
+	checkDestinations bool,
 ) error {
 
 	// setup logger, with the context that has the logger
@@ -148,6 +490,12 @@ func UpholdUpload(
 		logger.Panic().Err(err).Msg("failed to unmarshal input file")
 	}
 
+	// refuse to submit anything if the input pays a channel or destination twice
+	err = settlement.CheckForDuplicateTransactions(settlementState.Transactions, checkDestinations)
+	if err != nil {
+		return fmt.Errorf("failed duplicate check: %w", err)
+	}
+
 	settlementWallet, err := uphold.FromWalletInfo(ctx, settlementState.WalletInfo)
 	if err != nil {
 		logger.Panic().Err(err).Msg("failed to make settlement wallet")
@@ -155,21 +503,9 @@ func UpholdUpload(
 
 	// Read from the transaction log
 	logger.Info().Msg("scanning stateful logs to establish transaction status")
-	scanner := bufio.NewScanner(f)
-	isResubmit := false
-	for scanner.Scan() {
-		var tmp custodian.Transaction
-		err = json.Unmarshal(scanner.Bytes(), &tmp)
-		if err != nil {
-			logger.Panic().Err(err).Msg("failed to scan the transaction log")
-		}
-		for i := 0; i < len(settlementState.Transactions); i++ {
-			// Only one transaction per channel is allowed per settlement
-			if settlementState.Transactions[i].Channel == tmp.Channel {
-				isResubmit = true
-				settlementState.Transactions[i] = tmp
-			}
-		}
+	isResubmit, err := replayTransactionLog(f, settlementState.Transactions)
+	if err != nil {
+		logger.Panic().Err(err).Msg("failed to scan the transaction log")
 	}
 
 	// Optimize the case where we are rerunning by creating a truncated snapshot of the last state
@@ -238,9 +574,15 @@ func UpholdUpload(
 			continue
 		}
 
-		err = settlement.SubmitPreparedTransaction(ctx, settlementWallet, settlementTransaction)
+		// only transient errors are retried, a rejected transaction must not hold up the rest of the settlement.
+		// nothing is recorded in the log so the transaction is submitted again when the upload is rerun
+		err = submitWithRetry(ctx, settlementWallet, settlementTransaction, maxRetries)
 		if err != nil {
-			logger.Error().Err(err).Msg("unanticipated error")
+			if errorutils.IsErrTransient(err) {
+				logger.Error().Err(err).Msg("transient error persisted after retries")
+			} else {
+				logger.Error().Err(err).Msg("unanticipated error")
+			}
 			settlementTransaction.FailureReason = fmt.Sprintf("unanticipated error: %e", err)
 			allFinalized = false
 			continue
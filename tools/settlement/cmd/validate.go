@@ -0,0 +1,87 @@
+package settlement
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	cmdutils "github.com/brave-intl/bat-go/cmd"
+	rootcmd "github.com/brave-intl/bat-go/cmd"
+	"github.com/brave-intl/bat-go/libs/logging"
+	"github.com/brave-intl/bat-go/tools/settlement"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// ValidateAntifraudCmd validates antifraud settlement files before they are signed
+	ValidateAntifraudCmd = &cobra.Command{
+		Use:   "validate-antifraud INPUT_FILE...",
+		Short: "validates antifraud settlement files, exiting non-zero if any transaction is invalid",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   rootcmd.Perform("validate antifraud", RunValidateAntifraud),
+	}
+)
+
+func init() {
+	SettlementCmd.AddCommand(ValidateAntifraudCmd)
+
+	validateAntifraudBuilder := cmdutils.NewFlagBuilder(ValidateAntifraudCmd)
+
+	validateAntifraudBuilder.Flag().String("max-total-bat", "0",
+		"fail if the input files pay more than this much BAT in total, 0 disables the check").
+		Bind("max-total-bat")
+}
+
+// RunValidateAntifraud validates each antifraud settlement file, printing every problem found
+func RunValidateAntifraud(cmd *cobra.Command, args []string) error {
+	_, logger := logging.SetupLogger(cmd.Context())
+
+	maxTotalBATFlag, err := cmd.Flags().GetString("max-total-bat")
+	if err != nil {
+		return err
+	}
+	maxTotalBAT, err := decimal.NewFromString(maxTotalBATFlag)
+	if err != nil {
+		return fmt.Errorf("failed to parse max-total-bat: %w", err)
+	}
+	if maxTotalBAT.IsNegative() {
+		return errors.New("max-total-bat must not be negative")
+	}
+
+	var all []settlement.AntifraudTransaction
+	invalid := 0
+	for _, inputFile := range args {
+		settlementJSON, err := ioutil.ReadFile(inputFile)
+		if err != nil {
+			return err
+		}
+
+		var antifraudSettlements []settlement.AntifraudTransaction
+		if err := json.Unmarshal(settlementJSON, &antifraudSettlements); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", inputFile, err)
+		}
+
+		problems := settlement.ValidateAntifraudTransactions(antifraudSettlements)
+		for _, problem := range problems {
+			fmt.Printf("%s: %s\n", inputFile, problem)
+		}
+		invalid += len(problems)
+		all = append(all, antifraudSettlements...)
+
+		logger.Info().
+			Str("inputFile", inputFile).
+			Int("transactions", len(antifraudSettlements)).
+			Int("problems", len(problems)).
+			Msg("validated settlement file")
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("found %d problems, do not sign these settlement files", invalid)
+	}
+	if err := settlement.CheckTotalBAT(all, maxTotalBAT); err != nil {
+		return fmt.Errorf("%w, do not sign these settlement files", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package settlement
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	cmdutils "github.com/brave-intl/bat-go/cmd"
+	rootcmd "github.com/brave-intl/bat-go/cmd"
+	"github.com/brave-intl/bat-go/libs/custodian"
+	"github.com/brave-intl/bat-go/libs/logging"
+	"github.com/brave-intl/bat-go/tools/settlement"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// VerifyTotalsCmd checks finished settlement transactions against the totals eyeshade expects
+	VerifyTotalsCmd = &cobra.Command{
+		Use:   "verify-totals INPUT_FILE...",
+		Short: "checks finished settlement transactions reconcile with the totals eyeshade expects before upload",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   rootcmd.Perform("verify totals", RunVerifyTotals),
+	}
+)
+
+func init() {
+	SettlementCmd.AddCommand(VerifyTotalsCmd)
+
+	verifyTotalsBuilder := cmdutils.NewFlagBuilder(VerifyTotalsCmd)
+
+	verifyTotalsBuilder.Flag().String("expected", "",
+		"json file of the probi eyeshade expects to be paid per publisher (channel) or owner").
+		Bind("expected").
+		Require()
+}
+
+// RunVerifyTotals compares the eyeshade input files with the expected totals, exiting non-zero on any discrepancy
+func RunVerifyTotals(cmd *cobra.Command, args []string) error {
+	_, logger := logging.SetupLogger(cmd.Context())
+
+	expectedFile, err := cmd.Flags().GetString("expected")
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadFile(expectedFile)
+	if err != nil {
+		return err
+	}
+	var expected []settlement.ExpectedTotal
+	if err := json.Unmarshal(b, &expected); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", expectedFile, err)
+	}
+
+	var transactions []custodian.Transaction
+	for _, inputFile := range args {
+		txs, err := settlement.ReadSubmitLog(inputFile)
+		if err != nil {
+			return err
+		}
+		transactions = append(transactions, txs...)
+	}
+
+	discrepancies := settlement.CompareTotals(expected, transactions)
+	for _, discrepancy := range discrepancies {
+		fmt.Println(discrepancy)
+	}
+
+	logger.Info().
+		Int("expected", len(expected)).
+		Int("transactions", len(transactions)).
+		Int("discrepancies", len(discrepancies)).
+		Msg("verified settlement totals")
+
+	if len(discrepancies) > 0 {
+		return fmt.Errorf("%d totals do not reconcile, do not upload this settlement", len(discrepancies))
+	}
+	return nil
+}
@@ -10,7 +10,8 @@ import (
 
 // Config a space for complex inputs
 type Config struct {
-	Wallets map[string]string `yaml:"wallets"`
+	Wallets map[string]string      `yaml:"wallets"`
+	Fees    map[string]FeeSchedule `yaml:"fees"`
 }
 
 // GetWalletKey accesses the wallet config
@@ -0,0 +1,66 @@
+package settlement
+
+import (
+	"github.com/brave-intl/bat-go/libs/altcurrency"
+	"github.com/brave-intl/bat-go/libs/custodian"
+	"github.com/shopspring/decimal"
+)
+
+// FeeSchedule approximates the fees a provider charges on each payout, all amounts are in BAT
+// schedules are estimates for previewing a settlement, the fees charged are reported by the provider on submission
+type FeeSchedule struct {
+	// Percentage of each payout charged, 0.02 is 2%
+	Percentage float64 `yaml:"percentage"`
+	// PerTransactionBAT is a flat fee charged on each payout
+	PerTransactionBAT float64 `yaml:"perTransactionBat"`
+	// MaximumBAT caps the fee charged on a single payout, zero is uncapped
+	MaximumBAT float64 `yaml:"maximumBat"`
+	// Source describes where the schedule comes from and what it leaves out
+	Source string `yaml:"source"`
+}
+
+// DefaultFeeSchedules are used for providers without a fee schedule in the config
+var DefaultFeeSchedules = map[string]FeeSchedule{
+	"uphold": {
+		Source: "uphold does not charge BAT transfers between uphold cards, conversion by the recipient is not included",
+	},
+	"gemini": {
+		Source: "gemini does not charge BAT payouts to gemini accounts",
+	},
+	"bitflyer": {
+		Source: "bitflyer does not charge BAT transfers to bitflyer accounts",
+	},
+	"paypal": {
+		Percentage: 0.02,
+		Source:     "paypal mass pay charges 2% of each payment, per payment caps are not applied",
+	},
+}
+
+// EstimateFee returns the approximate fee charged on a payout of amount BAT
+func (fs FeeSchedule) EstimateFee(amount decimal.Decimal) decimal.Decimal {
+	fee := amount.Mul(decimal.NewFromFloat(fs.Percentage)).
+		Add(decimal.NewFromFloat(fs.PerTransactionBAT))
+	if maximum := decimal.NewFromFloat(fs.MaximumBAT); maximum.IsPositive() && fee.GreaterThan(maximum) {
+		return maximum
+	}
+	return fee
+}
+
+// EstimateFees returns the approximate total fee charged on the payouts
+func (fs FeeSchedule) EstimateFees(transactions []custodian.Transaction) decimal.Decimal {
+	total := decimal.Zero
+	for _, tx := range transactions {
+		total = total.Add(fs.EstimateFee(altcurrency.BAT.FromProbi(tx.Probi)))
+	}
+	return total
+}
+
+// FeeSchedule returns the fee schedule for provider, preferring the schedule from the config
+func (config *Config) FeeSchedule(provider string) FeeSchedule {
+	if config != nil {
+		if schedule, ok := config.Fees[provider]; ok {
+			return schedule
+		}
+	}
+	return DefaultFeeSchedules[provider]
+}
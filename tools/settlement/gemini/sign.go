@@ -5,6 +5,8 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
 
 	"github.com/brave-intl/bat-go/libs/clients/gemini"
 	vaultsigner "github.com/brave-intl/bat-go/tools/vault/signer"
@@ -60,3 +62,37 @@ func SignRequests(
 	}
 	return &privateRequestSequences, nil
 }
+
+// CheckNonceCollisions checks no two signed requests for the same api key have overlapping nonce ranges,
+// as gemini rejects the later of two requests which reuse a nonce
+func CheckNonceCollisions(requests []gemini.PrivateRequestSequence) error {
+	type nonceRange struct {
+		batch int
+		first int64
+		last  int64
+	}
+	byAPIKey := map[string][]nonceRange{}
+	for i, request := range requests {
+		byAPIKey[request.APIKey] = append(byAPIKey[request.APIKey], nonceRange{
+			batch: i,
+			first: request.Base.Nonce,
+			last:  request.Base.Nonce + int64(len(request.Signatures)) - 1,
+		})
+	}
+
+	for _, ranges := range byAPIKey {
+		sort.Slice(ranges, func(i, j int) bool {
+			return ranges[i].first < ranges[j].first
+		})
+		for i := 1; i < len(ranges); i++ {
+			previous, current := ranges[i-1], ranges[i]
+			if current.first <= previous.last {
+				return fmt.Errorf(
+					"gemini batches %d and %d have overlapping nonces %d-%d and %d-%d, do not submit these requests",
+					previous.batch, current.batch, previous.first, previous.last, current.first, current.last,
+				)
+			}
+		}
+	}
+	return nil
+}
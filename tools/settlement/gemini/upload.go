@@ -188,9 +188,37 @@ func ConvertTransactionsToPayouts(transactions *[]custodian.Transaction, txID st
 	return &payouts, total
 }
 
-// TransformTransactions splits the transactions into appropriately sized blocks for signing
+const (
+	// DefaultBatchSize is the number of payouts signed into each bulk payout request by default
+	DefaultBatchSize = 30
+	// MaxBatchSize is the largest bulk payout gemini accepts
+	MaxBatchSize = 500
+)
+
+// ValidateBatchSize checks the number of payouts per bulk payout request is one gemini accepts
+func ValidateBatchSize(batchSize int) error {
+	if batchSize < 1 || batchSize > MaxBatchSize {
+		return fmt.Errorf("gemini batch size must be between 1 and %d, got %d", MaxBatchSize, batchSize)
+	}
+	return nil
+}
+
+// TransformTransactions splits the transactions into blocks of the default batch size for signing
 func TransformTransactions(ctx context.Context, oauthClientID string, transactions []custodian.Transaction) (*[][]gemini.PayoutPayload, error) {
-	maxCount := 30
+	return TransformTransactionsN(ctx, oauthClientID, transactions, DefaultBatchSize)
+}
+
+// TransformTransactionsN splits the transactions into blocks of batchSize for signing
+func TransformTransactionsN(
+	ctx context.Context,
+	oauthClientID string,
+	transactions []custodian.Transaction,
+	batchSize int,
+) (*[][]gemini.PayoutPayload, error) {
+	if err := ValidateBatchSize(batchSize); err != nil {
+		return nil, err
+	}
+	maxCount := batchSize
 	blocksCount := (len(transactions) / maxCount) + 1
 	privateRequests := make([][]gemini.PayoutPayload, 0)
 	i := 0
@@ -15,9 +15,11 @@ import (
 var (
 	supportedCurrencies = map[string]float64{
 		"JPY": 0,
+		"USD": 2,
 	}
 	currencySymbols = map[string]string{
 		"JPY": "¥",
+		"USD": "$",
 	}
 )
 
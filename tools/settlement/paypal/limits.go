@@ -0,0 +1,55 @@
+package paypal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaxMassPayRows is the most payments paypal accepts in a single mass pay file
+const MaxMassPayRows = 5000
+
+// defaultMaxPayments are the largest single payments paypal allows per currency
+var defaultMaxPayments = map[string]decimal.Decimal{
+	"JPY": decimal.NewFromInt(1000000),
+}
+
+// Limits are checked while transforming payouts so a mass pay file is never written which paypal would reject
+type Limits struct {
+	// MaxRows is the most payments in a mass pay file
+	MaxRows int
+	// MaxPayment is the largest single payment in the settlement currency, zero is uncapped
+	MaxPayment decimal.Decimal
+	// MaxTotal is the largest total of a mass pay file in the settlement currency, zero is uncapped
+	MaxTotal decimal.Decimal
+}
+
+// DefaultLimits returns paypal's limits for mass payments in currency
+func DefaultLimits(currency string) Limits {
+	return Limits{
+		MaxRows:    MaxMassPayRows,
+		MaxPayment: defaultMaxPayments[currency],
+		MaxTotal:   decimal.Zero,
+	}
+}
+
+// exceedsPayment checks the amount against the per payment maximum
+func (l Limits) exceedsPayment(amount decimal.Decimal) bool {
+	return l.MaxPayment.IsPositive() && amount.GreaterThan(l.MaxPayment)
+}
+
+// limitError collects every limit exceeded so they can be fixed at once
+type limitError []string
+
+func (le limitError) Error() string {
+	return fmt.Sprintf("payouts exceed paypal mass pay limits:\n%s", strings.Join(le, "\n"))
+}
+
+// orNil returns the error only if a limit was exceeded
+func (le limitError) orNil() error {
+	if len(le) == 0 {
+		return nil
+	}
+	return le
+}
@@ -3,35 +3,85 @@ package paypal
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/brave-intl/bat-go/libs/clients/ratesclient"
 	"github.com/brave-intl/bat-go/libs/clients/ratios"
 	"github.com/brave-intl/bat-go/libs/custodian"
 	"github.com/shopspring/decimal"
 )
 
-// CalculateTransactionAmounts calculates the amount for each payout given a currency and rate
-func CalculateTransactionAmounts(currency string, rate decimal.Decimal, payouts *[]custodian.Transaction) (*[]custodian.Transaction, error) {
+// CalculateTransactionAmounts calculates the amount for each payout given a currency and rate, failing with the
+// position of every payout which exceeds the per payment limit
+func CalculateTransactionAmounts(
+	currency string,
+	rate decimal.Decimal,
+	payouts *[]custodian.Transaction,
+	limits Limits,
+) (*[]custodian.Transaction, error) {
 	txs := make([]custodian.Transaction, 0)
-	for _, tx := range *payouts {
+	var exceeded limitError
+	for i, tx := range *payouts {
 		if tx.WalletProvider != "paypal" {
 			continue
 		}
 		tx.Amount = exchangeFromProbi(tx.Probi, rate, currency)
 		tx.Currency = currency
+		if limits.exceedsPayment(tx.Amount) {
+			exceeded = append(exceeded, fmt.Sprintf("payout %d: channel %s is paid %s %s, more than the %s %s maximum",
+				i+1, tx.Channel, tx.Amount, currency, limits.MaxPayment, currency))
+		}
 		txs = append(txs, tx)
 	}
+	if err := exceeded.orNil(); err != nil {
+		return nil, err
+	}
 	return &txs, nil
 }
 
-// MergeAndTransformPayouts merges payouts to the same destination and transforms to paypal txn metadata
-func MergeAndTransformPayouts(batPayouts *[]custodian.Transaction) (*[]Metadata, error) {
+// SplitByCurrency groups the paypal payouts by the settlement currency of each payout, which must be one of
+// currencies. Every payout is assigned the only currency when a single currency is given
+func SplitByCurrency(payouts []custodian.Transaction, currencies []string) (map[string][]custodian.Transaction, error) {
+	byCurrency := make(map[string][]custodian.Transaction, len(currencies))
+	for _, currency := range currencies {
+		byCurrency[currency] = []custodian.Transaction{}
+	}
+	var unknown []string
+	for i, tx := range payouts {
+		if tx.WalletProvider != "paypal" {
+			continue
+		}
+		currency := tx.Currency
+		if len(currencies) == 1 {
+			currency = currencies[0]
+		}
+		if _, ok := byCurrency[currency]; !ok {
+			unknown = append(unknown, fmt.Sprintf("payout %d: channel %s has currency %q", i+1, tx.Channel, tx.Currency))
+			continue
+		}
+		byCurrency[currency] = append(byCurrency[currency], tx)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("payouts do not have one of the currencies %s:\n%s",
+			strings.Join(currencies, ","), strings.Join(unknown, "\n"))
+	}
+	return byCurrency, nil
+}
+
+// MergeAndTransformPayouts merges payouts to the same destination and transforms to paypal txn metadata,
+// failing if the merged payments exceed the limits of a mass pay file
+func MergeAndTransformPayouts(batPayouts *[]custodian.Transaction, limits Limits) (*[]Metadata, error) {
 	executedAt := time.Now().UTC()
 	rows := make([]Metadata, 0)
 	destinationToRow := map[string]*Metadata{}
+	destinationLines := map[string][]string{}
 
 	// FIXME refactor to separate merge and transform
-	for _, batPayout := range *batPayouts {
+	for i, batPayout := range *batPayouts {
 		destination := batPayout.Destination
 
 		var row *Metadata
@@ -54,31 +104,80 @@ func MergeAndTransformPayouts(batPayouts *[]custodian.Transaction) (*[]Metadata,
 		if err != nil {
 			return nil, err
 		}
+		destinationLines[destination] = append(destinationLines[destination], strconv.Itoa(i+1))
 	}
-	for _, row := range destinationToRow {
+
+	var exceeded limitError
+	total := decimal.Zero
+	for destination, row := range destinationToRow {
+		if limits.exceedsPayment(row.Amount) {
+			exceeded = append(exceeded, fmt.Sprintf("paypal payouts %s: merged payment to %s of %s %s is more than the %s %s maximum",
+				strings.Join(destinationLines[destination], ", "), destination, row.Amount, row.Currency, limits.MaxPayment, row.Currency))
+		}
+		total = total.Add(row.Amount)
 		rows = append(rows, *row)
 	}
+	sort.Strings(exceeded)
+	if limits.MaxRows > 0 && len(rows) > limits.MaxRows {
+		exceeded = append(exceeded, fmt.Sprintf("%d payments is more than the %d allowed in a mass pay file", len(rows), limits.MaxRows))
+	}
+	if limits.MaxTotal.IsPositive() && total.GreaterThan(limits.MaxTotal) {
+		exceeded = append(exceeded, fmt.Sprintf("total of %s is more than the %s allowed in a mass pay file", total, limits.MaxTotal))
+	}
+	if err := exceeded.orNil(); err != nil {
+		return nil, err
+	}
 	return &rows, nil
 }
 
-// GetRate figures out which rate to use
-func GetRate(ctx context.Context, currency string, rate decimal.Decimal) (decimal.Decimal, error) {
+// maxRateAge is the oldest rate that will be used for a payout
+const maxRateAge = 5 * time.Minute
+
+// RateCacheConfig configures the cache in front of the ratios service
+type RateCacheConfig struct {
+	// TTL is how long a fetched rate is reused, zero disables the cache
+	TTL time.Duration
+	// Stale is how long after TTL a rate may still be returned while it is refreshed
+	Stale time.Duration
+}
+
+// NewRatesClient returns the client payout rates are fetched with, the ratios service behind the
+// configured cache. Rates older than maxRateAge are rejected whether they are cached or not
+func NewRatesClient(ctx context.Context, cache RateCacheConfig) (ratesclient.Client, error) {
+	client, err := ratios.NewWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rates := ratesclient.NewRatios(client)
+	if cache.TTL > 0 {
+		rates = ratesclient.WithCache(rates, cache.TTL, ratesclient.StaleWhileRevalidate(cache.Stale))
+	}
+	return ratesclient.WithMaxAge(rates, maxRateAge), nil
+}
+
+// GetRate figures out which rate to use, a zero rate is fetched from rates
+// or from an uncached ratios client if rates is nil
+func GetRate(ctx context.Context, rates ratesclient.Client, currency string, rate decimal.Decimal) (decimal.Decimal, error) {
 	if rate.Equal(decimal.NewFromFloat(0)) {
-		client, err := ratios.NewWithContext(ctx)
-		if err != nil {
-			return rate, err
-		}
-		rateData, err := client.FetchRate(ctx, "BAT", currency)
-		if err != nil {
-			return rate, err
-		}
-		if rateData == nil {
-			return rate, errors.New("ratio not found")
-		}
-		rate = rateData.Payload[currency]
-		if time.Since(rateData.LastUpdated).Minutes() > 5 {
-			return rate, errors.New("ratios data is too old. update ratios response before moving forward")
+		if rates == nil {
+			var err error
+			rates, err = NewRatesClient(ctx, RateCacheConfig{})
+			if err != nil {
+				return rate, err
+			}
 		}
+		return getRate(ctx, rates, currency)
 	}
 	return rate, nil
 }
+
+func getRate(ctx context.Context, client ratesclient.Client, currency string) (decimal.Decimal, error) {
+	rate, err := client.GetRate(ctx, "BAT", currency)
+	if errors.Is(err, ratesclient.ErrStaleRate) {
+		return rate.Value, errors.New("ratios data is too old. update ratios response before moving forward")
+	}
+	if errors.Is(err, ratesclient.ErrRateNotFound) {
+		return rate.Value, errors.New("ratio not found")
+	}
+	return rate.Value, err
+}
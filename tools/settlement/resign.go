@@ -0,0 +1,88 @@
+package settlement
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/brave-intl/bat-go/libs/custodian"
+)
+
+// ReadSubmitLog reads the transactions recorded by a prior submission, either the json lines log written
+// during an uphold upload or a json array of transactions such as the categorized output of an upload
+func ReadSubmitLog(path string) ([]custodian.Transaction, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []custodian.Transaction
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &transactions); err != nil {
+			return nil, fmt.Errorf("failed to parse submit log %s: %w", path, err)
+		}
+		return transactions, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(b)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var tx custodian.Transaction
+		if err := json.Unmarshal(line, &tx); err != nil {
+			return nil, fmt.Errorf("failed to parse submit log %s: %w", path, err)
+		}
+		transactions = append(transactions, tx)
+	}
+	return transactions, scanner.Err()
+}
+
+// FilterUnsettled returns the antifraud transactions that did not settle in a prior submission, those missing
+// from the submitted transactions or whose latest recorded status is failed. Submitted transactions are matched
+// by channel, type and destination, later entries replacing earlier ones as in the upload log. A channel is only
+// unique within a single payout, so the type and destination keep the log of one payout, such as referrals, from
+// deciding the fate of another, such as contributions, when several logs are given. Transactions which are still pending
+// or whose status is unknown are returned separately and never as unsettled, as signing them again could pay
+// the channel twice
+func FilterUnsettled(
+	transactions []AntifraudTransaction,
+	submitted []custodian.Transaction,
+) (unsettled []AntifraudTransaction, inFlight []custodian.Transaction) {
+	latest := make(map[submissionKey]custodian.Transaction, len(submitted))
+	for _, tx := range submitted {
+		latest[keyOf(tx)] = tx
+	}
+
+	for _, at := range transactions {
+		tx, ok := latest[keyOf(at.Transaction)]
+		switch {
+		case !ok || tx.IsFailed():
+			unsettled = append(unsettled, at)
+		case isSettled(tx):
+		default:
+			inFlight = append(inFlight, tx)
+		}
+	}
+	return unsettled, inFlight
+}
+
+// submissionKey identifies a payment across the logs of several payouts
+type submissionKey struct {
+	Channel     string
+	Type        string
+	Destination string
+}
+
+func keyOf(tx custodian.Transaction) submissionKey {
+	return submissionKey{Channel: tx.Channel, Type: tx.Type, Destination: tx.Destination}
+}
+
+// isSettled checks for the completed status of each provider, uphold records completed and gemini complete
+func isSettled(tx custodian.Transaction) bool {
+	return tx.IsComplete() || tx.Status == "complete"
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -105,8 +106,18 @@ type State struct {
 	Transactions []custodian.Transaction `json:"transactions"`
 }
 
-// CheckForDuplicates in a list of transactions
-func CheckForDuplicates(transactions []AntifraudTransaction) error {
+// CheckForDuplicates in a list of transactions, by channel and when checkDestinations is set also by destination
+func CheckForDuplicates(transactions []AntifraudTransaction, checkDestinations bool) error {
+	settlementTransactions := make([]custodian.Transaction, 0, len(transactions))
+	for _, settlementTransaction := range transactions {
+		settlementTransactions = append(settlementTransactions, settlementTransaction.Transaction)
+	}
+	return CheckForDuplicateTransactions(settlementTransactions, checkDestinations)
+}
+
+// CheckForDuplicateTransactions in a list of prepared settlement transactions, by channel and when
+// checkDestinations is set also by destination, as different channels paid to one wallet pay its owner twice
+func CheckForDuplicateTransactions(transactions []custodian.Transaction, checkDestinations bool) error {
 	channelSet := map[string]bool{}
 	for _, settlementTransaction := range transactions {
 		if _, exists := channelSet[settlementTransaction.Channel]; exists {
@@ -114,6 +125,30 @@ func CheckForDuplicates(transactions []AntifraudTransaction) error {
 		}
 		channelSet[settlementTransaction.Channel] = true
 	}
+	if !checkDestinations {
+		return nil
+	}
+
+	byDestination := map[string][]string{}
+	for _, settlementTransaction := range transactions {
+		if settlementTransaction.Destination == "" {
+			continue
+		}
+		byDestination[settlementTransaction.Destination] = append(
+			byDestination[settlementTransaction.Destination],
+			fmt.Sprintf("%s (%s probi)", settlementTransaction.Channel, settlementTransaction.Probi),
+		)
+	}
+	collisions := []string{}
+	for destination, channels := range byDestination {
+		if len(channels) > 1 {
+			collisions = append(collisions, destination+": "+strings.Join(channels, ", "))
+		}
+	}
+	if len(collisions) > 0 {
+		sort.Strings(collisions)
+		return errors.New("DO NOT PROCEED WITH PAYOUT: Malformed settlement file, duplicate destinations detected!: " + strings.Join(collisions, "; "))
+	}
 	return nil
 }
 
@@ -5,6 +5,9 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/brave-intl/bat-go/libs/altcurrency"
@@ -355,3 +358,323 @@ func TestUnmarshalCreatorsTransaction(t *testing.T) {
 		t.Fatal("Converted transaction does not match")
 	}
 }
+
+func TestValidateAntifraudTransactions(t *testing.T) {
+	settlementJSON := []byte(`[
+		{
+			"address": "5e14c5b2-8651-427d-905e-b078513b6fc3",
+			"bat": "0.7125",
+			"payout_report_id": "4520e913-664e-479e-a58c-357cf750b00a",
+			"publisher": "twitch#author:valid",
+			"type": "contribution",
+			"wallet_provider_id": "uphold#id:6c0397f3-df41-440a-9fbb-b517e1142a9a"
+		},
+		{
+			"address": "5e14c5b2-8651-427d-905e-b078513b6fc3",
+			"bat": "0",
+			"publisher": "twitch#author:zero",
+			"type": "contribution",
+			"wallet_provider_id": "uphold#id:6c0397f3-df41-440a-9fbb-b517e1142a9a"
+		},
+		{
+			"address": "5e14c5b2-8651-427d-905e-b078513b6fc3",
+			"bat": "1",
+			"publisher": "twitch#author:malformed",
+			"type": "contribution",
+			"wallet_provider_id": "uphold"
+		},
+		{
+			"address": "5e14c5b2-8651-427d-905e-b078513b6fc3",
+			"bat": "1",
+			"publisher": "twitch#author:unknown-provider",
+			"type": "contribution",
+			"wallet_provider_id": "coinbase#id:6c0397f3-df41-440a-9fbb-b517e1142a9a"
+		},
+		{
+			"address": "5e14c5b2-8651-427d-905e-b078513b6fc3",
+			"bat": "1",
+			"publisher": "twitch#author:unknown-type",
+			"type": "tip",
+			"wallet_provider_id": "uphold#id:6c0397f3-df41-440a-9fbb-b517e1142a9a"
+		},
+		{
+			"address": "not a card",
+			"bat": "1",
+			"publisher": "twitch#author:bad-destination",
+			"type": "referral",
+			"wallet_provider_id": "uphold#id:6c0397f3-df41-440a-9fbb-b517e1142a9a"
+		},
+		{
+			"address": "5e14c5b2-8651-427d-905e-b078513b6fc3",
+			"bat": "1",
+			"publisher": "twitch#author:valid",
+			"type": "contribution",
+			"wallet_provider_id": "uphold#id:6c0397f3-df41-440a-9fbb-b517e1142a9a"
+		}
+	]`)
+
+	var afTransactions []AntifraudTransaction
+	if err := json.Unmarshal(settlementJSON, &afTransactions); err != nil {
+		t.Fatal(err)
+	}
+
+	problems := ValidateAntifraudTransactions(afTransactions)
+
+	expectedIndices := []int{1, 2, 3, 4, 5, 6}
+	if len(problems) != len(expectedIndices) {
+		t.Fatalf("expected %d problems, found %d: %v", len(expectedIndices), len(problems), problems)
+	}
+	for i, problem := range problems {
+		if problem.Index != expectedIndices[i] {
+			t.Errorf("expected problem with transaction %d, found %s", expectedIndices[i], problem)
+		}
+	}
+}
+
+func TestFilterUnsettled(t *testing.T) {
+	var transactions []AntifraudTransaction
+	for _, channel := range []string{"completed", "complete", "failed", "retried", "pending", "processing", "missing"} {
+		transactions = append(transactions, AntifraudTransaction{Transaction: custodian.Transaction{Channel: channel}})
+	}
+
+	submitted := []custodian.Transaction{
+		{Channel: "completed", Status: "processing"},
+		{Channel: "completed", Status: "completed"},
+		{Channel: "complete", Status: "complete"},
+		{Channel: "failed", Status: "failed"},
+		{Channel: "retried", Status: "completed"},
+		{Channel: "retried", Status: "failed"},
+		{Channel: "pending", Status: "pending"},
+		{Channel: "processing", Status: "processing"},
+	}
+
+	unsettled, inFlight := FilterUnsettled(transactions, submitted)
+
+	var unsettledChannels []string
+	for _, at := range unsettled {
+		unsettledChannels = append(unsettledChannels, at.Channel)
+	}
+	if !reflect.DeepEqual(unsettledChannels, []string{"failed", "retried", "missing"}) {
+		t.Errorf("unexpected unsettled transactions %v", unsettledChannels)
+	}
+
+	var inFlightChannels []string
+	for _, tx := range inFlight {
+		inFlightChannels = append(inFlightChannels, tx.Channel)
+	}
+	if !reflect.DeepEqual(inFlightChannels, []string{"pending", "processing"}) {
+		t.Errorf("unexpected in flight transactions %v", inFlightChannels)
+	}
+}
+
+func TestFilterUnsettledAcrossPayouts(t *testing.T) {
+	contribution := AntifraudTransaction{Transaction: custodian.Transaction{
+		Channel: "a", Type: "contribution", Destination: "dest-a",
+	}}
+	referral := AntifraudTransaction{Transaction: custodian.Transaction{
+		Channel: "a", Type: "referral", Destination: "dest-a",
+	}}
+
+	// the contribution to the channel failed and was then paid by a later payout as a referral
+	submitted := []custodian.Transaction{
+		{Channel: "a", Type: "contribution", Destination: "dest-a", Status: "failed"},
+		{Channel: "a", Type: "referral", Destination: "dest-a", Status: "completed"},
+	}
+
+	unsettled, inFlight := FilterUnsettled([]AntifraudTransaction{contribution, referral}, submitted)
+	if len(unsettled) != 1 || unsettled[0].Type != "contribution" {
+		t.Errorf("expected only the failed contribution to be unsettled, found %v", unsettled)
+	}
+	if len(inFlight) != 0 {
+		t.Errorf("unexpected in flight transactions %v", inFlight)
+	}
+
+	// a payment to another destination is not the same payment
+	moved := AntifraudTransaction{Transaction: custodian.Transaction{
+		Channel: "a", Type: "referral", Destination: "dest-b",
+	}}
+	unsettled, _ = FilterUnsettled([]AntifraudTransaction{moved}, submitted)
+	if len(unsettled) != 1 {
+		t.Errorf("expected the payment to a new destination to be unsettled, found %v", unsettled)
+	}
+}
+
+func TestReadSubmitLog(t *testing.T) {
+	dir := t.TempDir()
+
+	logFile := filepath.Join(dir, "settlement-log.json")
+	err := os.WriteFile(logFile, []byte(`{"publisher":"a","status":"processing"}
+{"publisher":"a","status":"completed"}
+
+{"publisher":"b","status":"failed"}
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs, err := ReadSubmitLog(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 3 || txs[1].Status != "completed" || txs[2].Channel != "b" {
+		t.Errorf("unexpected transactions from log %+v", txs)
+	}
+
+	outputFile := filepath.Join(dir, "settlement-complete.json")
+	err = os.WriteFile(outputFile, []byte(`[
+  {"publisher":"c","status":"complete"}
+]`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs, err = ReadSubmitLog(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 1 || txs[0].Channel != "c" {
+		t.Errorf("unexpected transactions from output %+v", txs)
+	}
+}
+
+func TestFeeScheduleEstimateFees(t *testing.T) {
+	schedule := FeeSchedule{Percentage: 0.02, PerTransactionBAT: 0.1, MaximumBAT: 1}
+
+	cases := []struct {
+		amount   string
+		expected string
+	}{
+		{"0", "0.1"},
+		{"10", "0.3"},
+		{"45", "1"},
+		{"1000", "1"},
+	}
+	for _, c := range cases {
+		fee := schedule.EstimateFee(decimal.RequireFromString(c.amount))
+		if !fee.Equal(decimal.RequireFromString(c.expected)) {
+			t.Errorf("expected fee of %s on %s, found %s", c.expected, c.amount, fee)
+		}
+	}
+
+	alt := altcurrency.BAT
+	transactions := []custodian.Transaction{
+		{Probi: alt.ToProbi(decimal.NewFromInt(10))},
+		{Probi: alt.ToProbi(decimal.NewFromInt(1000))},
+	}
+	if total := schedule.EstimateFees(transactions); !total.Equal(decimal.RequireFromString("1.3")) {
+		t.Errorf("expected total fee of 1.3, found %s", total)
+	}
+
+	uncapped := FeeSchedule{Percentage: 0.02}
+	if total := uncapped.EstimateFees(transactions); !total.Equal(decimal.RequireFromString("20.2")) {
+		t.Errorf("expected total fee of 20.2, found %s", total)
+	}
+}
+
+func TestConfigFeeSchedule(t *testing.T) {
+	var config *Config
+	if schedule := config.FeeSchedule("paypal"); schedule.Percentage != DefaultFeeSchedules["paypal"].Percentage {
+		t.Error("expected the default schedule without a config")
+	}
+
+	config = &Config{Fees: map[string]FeeSchedule{"paypal": {Percentage: 0.01, Source: "negotiated rate"}}}
+	if schedule := config.FeeSchedule("paypal"); schedule.Source != "negotiated rate" {
+		t.Error("expected the configured schedule to be preferred")
+	}
+	if schedule := config.FeeSchedule("uphold"); schedule.Source != DefaultFeeSchedules["uphold"].Source {
+		t.Error("expected the default schedule for providers not in the config")
+	}
+}
+
+func TestCompareTotals(t *testing.T) {
+	probi := func(bat int64) decimal.Decimal {
+		return altcurrency.BAT.ToProbi(decimal.NewFromInt(bat))
+	}
+
+	transactions := []custodian.Transaction{
+		{Channel: "matching.com", Publisher: "owner-a", Probi: probi(5), Status: "completed"},
+		{Channel: "short.com", Publisher: "owner-a", Probi: probi(3), Status: "completed"},
+		{Channel: "short.com", Publisher: "owner-a", Probi: probi(2), Status: "failed"},
+		{Channel: "owned-1.com", Publisher: "owner-b", Probi: probi(1), Status: "complete"},
+		{Channel: "owned-2.com", Publisher: "owner-b", Probi: probi(2), Status: "completed"},
+		{Channel: "unexpected.com", Publisher: "owner-c", Probi: probi(4), Status: "completed"},
+		{Channel: "unexpected-failed.com", Publisher: "owner-c", Probi: probi(4), Status: "failed"},
+	}
+	expected := []ExpectedTotal{
+		{Channel: "matching.com", Probi: probi(5)},
+		{Channel: "short.com", Probi: probi(5)},
+		{Owner: "owner-b", Probi: probi(3)},
+		{Channel: "missing.com", Probi: probi(1)},
+	}
+
+	discrepancies := CompareTotals(expected, transactions)
+
+	var found []string
+	for _, discrepancy := range discrepancies {
+		found = append(found, discrepancy.String())
+	}
+	wanted := []string{
+		"channel missing.com: expected 1 BAT, settled 0 BAT",
+		"channel short.com: expected 5 BAT, settled 3 BAT, 2 BAT not settled",
+		"channel unexpected.com: expected 0 BAT, settled 4 BAT",
+	}
+	if !reflect.DeepEqual(found, wanted) {
+		t.Errorf("unexpected discrepancies\nwanted: %q\nfound: %q", wanted, found)
+	}
+}
+
+func TestCheckForDuplicates(t *testing.T) {
+	transactions := []AntifraudTransaction{
+		{Transaction: custodian.Transaction{Channel: "a.com", Destination: "wallet-1", Probi: decimal.New(1, 18)}},
+		{Transaction: custodian.Transaction{Channel: "b.com", Destination: "wallet-2", Probi: decimal.New(2, 18)}},
+		{Transaction: custodian.Transaction{Channel: "c.com", Destination: "wallet-1", Probi: decimal.New(3, 18)}},
+	}
+
+	if err := CheckForDuplicates(transactions, false); err != nil {
+		t.Errorf("expected channels to be unique, got %v", err)
+	}
+
+	err := CheckForDuplicates(transactions, true)
+	if err == nil {
+		t.Fatal("expected duplicate destinations to be detected")
+	}
+	for _, expected := range []string{"wallet-1", "a.com", "c.com"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q to list %s", err, expected)
+		}
+	}
+	if strings.Contains(err.Error(), "b.com") {
+		t.Errorf("expected %q to only list colliding transactions", err)
+	}
+
+	transactions = append(transactions, AntifraudTransaction{
+		Transaction: custodian.Transaction{Channel: "a.com", Destination: "wallet-3"},
+	})
+	if err := CheckForDuplicates(transactions, false); err == nil {
+		t.Error("expected duplicate channels to be detected")
+	}
+}
+
+func TestCheckTotalBAT(t *testing.T) {
+	transactions := []AntifraudTransaction{
+		{BAT: decimal.NewFromFloat(1.5)},
+		{Transaction: custodian.Transaction{Probi: decimal.New(25, 17)}},
+	}
+	if total := TotalBAT(transactions); !total.Equal(decimal.NewFromInt(4)) {
+		t.Errorf("expected a total of 4 BAT, got %s", total)
+	}
+
+	cases := []struct {
+		maximum decimal.Decimal
+		err     bool
+	}{
+		{maximum: decimal.Zero},
+		{maximum: decimal.NewFromInt(4)},
+		{maximum: decimal.NewFromFloat(3.999999), err: true},
+		{maximum: decimal.NewFromInt(5)},
+	}
+	for _, c := range cases {
+		err := CheckTotalBAT(transactions, c.maximum)
+		if (err != nil) != c.err {
+			t.Errorf("maximum %s: unexpected error %v", c.maximum, err)
+		}
+	}
+}
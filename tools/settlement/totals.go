@@ -0,0 +1,128 @@
+package settlement
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/brave-intl/bat-go/libs/altcurrency"
+	"github.com/brave-intl/bat-go/libs/custodian"
+	"github.com/shopspring/decimal"
+)
+
+// ExpectedTotal is the amount eyeshade expects to be paid to a channel, or to every channel of an owner
+// when no channel is set
+type ExpectedTotal struct {
+	Channel string          `json:"publisher,omitempty"`
+	Owner   string          `json:"owner,omitempty"`
+	Probi   decimal.Decimal `json:"probi"`
+}
+
+// key identifies the channel or owner the total is expected for
+func (et ExpectedTotal) key() string {
+	if et.Channel != "" {
+		return "channel " + et.Channel
+	}
+	return "owner " + et.Owner
+}
+
+// TotalDiscrepancy is a channel or owner whose settled payouts do not match the expected total
+type TotalDiscrepancy struct {
+	Key      string
+	Expected decimal.Decimal
+	Settled  decimal.Decimal
+	// Unsettled is the amount of transactions which were not settled, such as failed payouts
+	Unsettled decimal.Decimal
+}
+
+// String describes the discrepancy in BAT
+func (td TotalDiscrepancy) String() string {
+	s := fmt.Sprintf("%s: expected %s BAT, settled %s BAT",
+		td.Key, altcurrency.BAT.FromProbi(td.Expected), altcurrency.BAT.FromProbi(td.Settled))
+	if !td.Unsettled.IsZero() {
+		s += fmt.Sprintf(", %s BAT not settled", altcurrency.BAT.FromProbi(td.Unsettled))
+	}
+	return s
+}
+
+// CompareTotals compares the payouts of finished settlement transactions with the totals eyeshade expected,
+// returning every channel or owner that does not reconcile, sorted by key. Transactions which were not settled
+// do not count towards the totals and payouts to channels or owners with no expected total are discrepancies
+func CompareTotals(expected []ExpectedTotal, transactions []custodian.Transaction) []TotalDiscrepancy {
+	type sums struct {
+		settled   decimal.Decimal
+		unsettled decimal.Decimal
+	}
+	var (
+		byChannel = map[string]*sums{}
+		byOwner   = map[string]*sums{}
+	)
+	add := func(m map[string]*sums, key string, tx custodian.Transaction) {
+		s, ok := m[key]
+		if !ok {
+			s = &sums{settled: decimal.Zero, unsettled: decimal.Zero}
+			m[key] = s
+		}
+		if isSettled(tx) {
+			s.settled = s.settled.Add(tx.Probi)
+		} else {
+			s.unsettled = s.unsettled.Add(tx.Probi)
+		}
+	}
+	for _, tx := range transactions {
+		add(byChannel, tx.Channel, tx)
+		add(byOwner, tx.Publisher, tx)
+	}
+
+	var (
+		discrepancies   []TotalDiscrepancy
+		coveredChannels = map[string]bool{}
+		coveredOwners   = map[string]bool{}
+	)
+	for _, et := range expected {
+		s := &sums{settled: decimal.Zero, unsettled: decimal.Zero}
+		if et.Channel != "" {
+			coveredChannels[et.Channel] = true
+			if found, ok := byChannel[et.Channel]; ok {
+				s = found
+			}
+		} else {
+			coveredOwners[et.Owner] = true
+			if found, ok := byOwner[et.Owner]; ok {
+				s = found
+			}
+		}
+		if !s.settled.Equal(et.Probi) {
+			discrepancies = append(discrepancies, TotalDiscrepancy{
+				Key:       et.key(),
+				Expected:  et.Probi,
+				Settled:   s.settled,
+				Unsettled: s.unsettled,
+			})
+		}
+	}
+
+	// payouts nobody expected, totalled by channel
+	unexpected := map[string]*sums{}
+	for _, tx := range transactions {
+		if coveredChannels[tx.Channel] || coveredOwners[tx.Publisher] {
+			continue
+		}
+		add(unexpected, tx.Channel, tx)
+	}
+	for channel, s := range unexpected {
+		if s.settled.IsZero() {
+			continue
+		}
+		discrepancies = append(discrepancies, TotalDiscrepancy{
+			Key:       "channel " + channel,
+			Expected:  decimal.Zero,
+			Settled:   s.settled,
+			Unsettled: s.unsettled,
+		})
+	}
+
+	sort.Slice(discrepancies, func(i, j int) bool {
+		return discrepancies[i].Key < discrepancies[j].Key
+	})
+	return discrepancies
+}
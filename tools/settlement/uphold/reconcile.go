@@ -0,0 +1,82 @@
+package uphold
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/brave-intl/bat-go/libs/custodian"
+	upholdwallet "github.com/brave-intl/bat-go/libs/wallet/provider/uphold"
+	"github.com/shopspring/decimal"
+)
+
+// TransactionDetailGetter looks up the complete uphold record of a transaction
+type TransactionDetailGetter interface {
+	GetTransactionDetail(ctx context.Context, id string) (*upholdwallet.TransactionDetail, error)
+}
+
+// ReconciliationRow compares a settlement transaction with the transaction uphold recorded for it
+type ReconciliationRow struct {
+	Channel           string          `csv:"channel"`
+	ProviderID        string          `csv:"transaction id"`
+	WalletProviderID  string          `csv:"expected destination"`
+	UpholdDestination string          `csv:"uphold destination"`
+	Amount            decimal.Decimal `csv:"expected amount"`
+	UpholdAmount      decimal.Decimal `csv:"uphold amount"`
+	Status            string          `csv:"expected status"`
+	UpholdStatus      string          `csv:"uphold status"`
+	UpholdFees        decimal.Decimal `csv:"uphold fees"`
+	UpholdCreatedAt   string          `csv:"uphold created at"`
+	Discrepancies     string          `csv:"discrepancies"`
+}
+
+// HasDiscrepancy returns true if the settlement and uphold records of the transaction disagree
+func (row ReconciliationRow) HasDiscrepancy() bool {
+	return row.Discrepancies != ""
+}
+
+// Reconcile looks up each submitted settlement transaction on uphold and reports where the records disagree
+// transactions that were never submitted are reported as such without being looked up
+func Reconcile(ctx context.Context, getter TransactionDetailGetter, settlements []custodian.Transaction) []ReconciliationRow {
+	rows := make([]ReconciliationRow, 0, len(settlements))
+	for _, tx := range settlements {
+		if tx.WalletProvider != "uphold" {
+			continue
+		}
+
+		row := ReconciliationRow{
+			Channel:          tx.Channel,
+			ProviderID:       tx.ProviderID,
+			WalletProviderID: tx.WalletProviderID,
+			Amount:           tx.Amount,
+			Status:           tx.Status,
+		}
+
+		var discrepancies []string
+		if tx.ProviderID == "" {
+			discrepancies = append(discrepancies, "not submitted")
+		} else if detail, err := getter.GetTransactionDetail(ctx, tx.ProviderID); err != nil {
+			discrepancies = append(discrepancies, "lookup failed: "+err.Error())
+		} else {
+			row.UpholdDestination = detail.Destination.CardID
+			row.UpholdAmount = detail.Destination.Amount
+			row.UpholdStatus = detail.Status
+			row.UpholdFees = detail.TotalFees(detail.Destination.Currency)
+			row.UpholdCreatedAt = detail.CreatedAt.UTC().Format(time.RFC3339)
+
+			if detail.Destination.CardID != tx.WalletProviderID {
+				discrepancies = append(discrepancies, "destination mismatch")
+			}
+			if !detail.Destination.Amount.Equal(tx.Amount) {
+				discrepancies = append(discrepancies, "amount mismatch")
+			}
+			if detail.Status != tx.Status {
+				discrepancies = append(discrepancies, "status mismatch")
+			}
+		}
+
+		row.Discrepancies = strings.Join(discrepancies, "; ")
+		rows = append(rows, row)
+	}
+	return rows
+}
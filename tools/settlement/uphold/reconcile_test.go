@@ -0,0 +1,78 @@
+package uphold
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/brave-intl/bat-go/libs/custodian"
+	upholdwallet "github.com/brave-intl/bat-go/libs/wallet/provider/uphold"
+	"github.com/shopspring/decimal"
+	"gotest.tools/assert"
+)
+
+type fakeDetailGetter map[string]*upholdwallet.TransactionDetail
+
+func (f fakeDetailGetter) GetTransactionDetail(ctx context.Context, id string) (*upholdwallet.TransactionDetail, error) {
+	detail, ok := f[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return detail, nil
+}
+
+func detailFor(tx custodian.Transaction) *upholdwallet.TransactionDetail {
+	return &upholdwallet.TransactionDetail{
+		ID:     tx.ProviderID,
+		Status: tx.Status,
+		Destination: upholdwallet.TransactionDetailParty{
+			CardID:   tx.WalletProviderID,
+			Currency: "BAT",
+			Amount:   tx.Amount,
+		},
+		Fees: []upholdwallet.TransactionDetailFee{
+			{Currency: "BAT", Amount: decimal.RequireFromString("0.25")},
+		},
+	}
+}
+
+// TestReconcile tests Reconcile
+func TestReconcile(t *testing.T) {
+	matching := custodian.Transaction{
+		Channel:          "brave.com",
+		ProviderID:       "tx-1",
+		WalletProvider:   "uphold",
+		WalletProviderID: "card-1",
+		Amount:           decimal.NewFromInt(10),
+		Status:           "completed",
+	}
+	mismatched := matching
+	mismatched.Channel = "example.com"
+	mismatched.ProviderID = "tx-2"
+	unsubmitted := matching
+	unsubmitted.Channel = "example.org"
+	unsubmitted.ProviderID = ""
+	missing := matching
+	missing.Channel = "example.net"
+	missing.ProviderID = "tx-3"
+	otherProvider := matching
+	otherProvider.WalletProvider = "gemini"
+
+	getter := fakeDetailGetter{
+		"tx-1": detailFor(matching),
+		"tx-2": detailFor(mismatched),
+	}
+	getter["tx-2"].Status = "failed"
+	getter["tx-2"].Destination.Amount = decimal.NewFromInt(9)
+
+	rows := Reconcile(context.Background(), getter,
+		[]custodian.Transaction{matching, mismatched, unsubmitted, missing, otherProvider})
+	assert.Equal(t, 4, len(rows))
+
+	assert.Assert(t, !rows[0].HasDiscrepancy(), rows[0].Discrepancies)
+	assert.Assert(t, rows[0].UpholdFees.Equal(decimal.RequireFromString("0.25")))
+
+	assert.Equal(t, "amount mismatch; status mismatch", rows[1].Discrepancies)
+	assert.Equal(t, "not submitted", rows[2].Discrepancies)
+	assert.Equal(t, "lookup failed: not found", rows[3].Discrepancies)
+}
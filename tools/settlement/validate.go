@@ -0,0 +1,137 @@
+package settlement
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/brave-intl/bat-go/libs/altcurrency"
+	uuid "github.com/satori/go.uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ProviderTransactionTypes maps each settlement provider to the transaction types it is paid out with
+// providers with a single type pay out every transaction from the same wallet
+var ProviderTransactionTypes = map[string][]string{
+	"uphold":   {"contribution", "referral", "adsDirectDeposit"},
+	"paypal":   {"default"},
+	"gemini":   {"contribution", "referral", "adsDirectDeposit"},
+	"bitflyer": {"default"},
+}
+
+// AntifraudValidationError describes why the antifraud transaction at Index of a settlement file is invalid
+type AntifraudValidationError struct {
+	Index   int
+	Channel string
+	Err     error
+}
+
+// Error implements error
+func (e AntifraudValidationError) Error() string {
+	return fmt.Sprintf("transaction %d (%s): %s", e.Index, e.Channel, e.Err)
+}
+
+// Unwrap returns the underlying validation error
+func (e AntifraudValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateAntifraudTransactions checks every transaction of an antifraud settlement file, returning all of the
+// problems found rather than stopping at the first so that a file can be corrected in one pass
+func ValidateAntifraudTransactions(transactions []AntifraudTransaction) []AntifraudValidationError {
+	var (
+		problems []AntifraudValidationError
+		channels = map[string]int{}
+	)
+	for i, at := range transactions {
+		invalid := func(err error) {
+			problems = append(problems, AntifraudValidationError{Index: i, Channel: at.Channel, Err: err})
+		}
+
+		if first, exists := channels[at.Channel]; exists {
+			invalid(fmt.Errorf("duplicate payment, channel is also paid by transaction %d", first))
+		} else {
+			channels[at.Channel] = i
+		}
+
+		// guard the conversion, which assumes these are well formed
+		if at.BAT.GreaterThan(decimal.Zero) && len(at.WalletProviderInfo) > 0 && !validWalletProviderInfo(at.WalletProviderInfo) {
+			invalid(fmt.Errorf("malformed wallet provider id %q", at.WalletProviderInfo))
+			continue
+		}
+		if !at.BAT.GreaterThan(decimal.Zero) && at.Probi.GreaterThan(decimal.Zero) && at.AltCurrency == nil {
+			invalid(errors.New("missing altcurrency"))
+			continue
+		}
+
+		tx, err := at.ToTransaction()
+		if err != nil {
+			invalid(err)
+			continue
+		}
+
+		types, ok := ProviderTransactionTypes[tx.WalletProvider]
+		if !ok {
+			invalid(fmt.Errorf("unknown wallet provider %q", tx.WalletProvider))
+			continue
+		}
+		if len(types) > 1 && !contains(types, tx.Type) {
+			invalid(fmt.Errorf("unknown transaction type %q for %s", tx.Type, tx.WalletProvider))
+		}
+
+		if strings.IndexFunc(tx.Destination, unicode.IsSpace) >= 0 {
+			invalid(fmt.Errorf("malformed destination %q", tx.Destination))
+		} else if tx.WalletProvider == "uphold" {
+			// uphold destinations are card ids
+			if _, err := uuid.FromString(tx.Destination); err != nil {
+				invalid(fmt.Errorf("malformed uphold destination %q", tx.Destination))
+			}
+		}
+	}
+	return problems
+}
+
+// TotalBAT sums the BAT paid by the antifraud transactions
+func TotalBAT(transactions []AntifraudTransaction) decimal.Decimal {
+	total := decimal.Zero
+	for _, at := range transactions {
+		if at.BAT.GreaterThan(decimal.Zero) {
+			total = total.Add(at.BAT)
+		} else {
+			total = total.Add(altcurrency.BAT.FromProbi(at.Probi))
+		}
+	}
+	return total
+}
+
+// CheckTotalBAT guards against a malformed settlement, such as one with an extra zero, paying out more than
+// maximum BAT in total. A maximum of zero disables the check
+func CheckTotalBAT(transactions []AntifraudTransaction, maximum decimal.Decimal) error {
+	if !maximum.IsPositive() {
+		return nil
+	}
+	if total := TotalBAT(transactions); total.GreaterThan(maximum) {
+		return fmt.Errorf("settlement pays %s BAT in total, more than the maximum of %s BAT", total, maximum)
+	}
+	return nil
+}
+
+// validWalletProviderInfo checks the wallet provider id has the form establishment#type:id
+func validWalletProviderInfo(info string) bool {
+	establishment, typeAndID, found := strings.Cut(info, "#")
+	if !found || establishment == "" {
+		return false
+	}
+	kind, id, found := strings.Cut(typeAndID, ":")
+	return found && kind != "" && id != ""
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
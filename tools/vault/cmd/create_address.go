@@ -0,0 +1,89 @@
+package vault
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	cmdutils "github.com/brave-intl/bat-go/cmd"
+	rootcmd "github.com/brave-intl/bat-go/cmd"
+	logutils "github.com/brave-intl/bat-go/libs/logging"
+	"github.com/brave-intl/bat-go/libs/wallet"
+	"github.com/brave-intl/bat-go/libs/wallet/provider/uphold"
+	vaultsigner "github.com/brave-intl/bat-go/tools/vault/signer"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ed25519"
+)
+
+var (
+	// CreateAddressCmd creates a deposit address on an existing wallet
+	CreateAddressCmd = &cobra.Command{
+		Use:   "create-address WALLET_NAME",
+		Short: "creates a new deposit address on the uphold card of an existing wallet",
+		Args:  cobra.ExactArgs(1),
+		Run:   rootcmd.Perform("create address", CreateAddress),
+	}
+)
+
+func init() {
+	VaultCmd.AddCommand(
+		CreateAddressCmd,
+	)
+
+	createAddressBuilder := cmdutils.NewFlagBuilder(CreateAddressCmd)
+
+	createAddressBuilder.Flag().String("network", "ethereum",
+		"the network to create the deposit address on, e.g. ethereum, bitcoin or litecoin").
+		Bind("network")
+}
+
+// CreateAddress creates a deposit address on the uphold card of a wallet set up with create-wallet
+// the wallet is not re-registered and no keys are generated
+func CreateAddress(command *cobra.Command, args []string) error {
+	ctx := command.Context()
+	_, logger := logutils.SetupLogger(ctx)
+
+	name := args[0]
+	network, err := command.Flags().GetString("network")
+	if err != nil {
+		return err
+	}
+	network = strings.ToLower(strings.TrimSpace(network))
+	if network == "" {
+		return errors.New("network is required")
+	}
+
+	wrappedClient, err := vaultsigner.Connect()
+	if err != nil {
+		return err
+	}
+
+	response, err := wrappedClient.Client.Logical().Read("wallets/" + name)
+	if err != nil {
+		return err
+	}
+	if response == nil {
+		return fmt.Errorf("no wallet named %s, create it with create-wallet", name)
+	}
+	providerID, ok := response.Data["providerId"].(string)
+	if !ok || providerID == "" {
+		return errors.New("invalid wallet name")
+	}
+
+	// creating an address only requires the card id, requests are authorized by the uphold access token
+	w := uphold.Wallet{Info: wallet.Info{Provider: "uphold", ProviderID: providerID}, PrivKey: ed25519.PrivateKey{}}
+
+	address, err := w.CreateCardAddress(ctx, network)
+	if err != nil {
+		return err
+	}
+
+	logger.Info().
+		Str("name", name).
+		Str("card_id", providerID).
+		Str("network", network).
+		Str("address", address).
+		Msg("created deposit addr")
+	fmt.Println(address)
+	return nil
+}
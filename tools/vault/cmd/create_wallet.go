@@ -1,8 +1,10 @@
 package vault
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/brave-intl/bat-go/libs/wallet"
 	"github.com/brave-intl/bat-go/libs/wallet/provider/uphold"
 	vaultsigner "github.com/brave-intl/bat-go/tools/vault/signer"
+	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 	"golang.org/x/crypto/ed25519"
 )
@@ -43,6 +46,10 @@ func init() {
 	createWalletBuilder.Flag().Bool("offline", false,
 		"operate in multi-step offline mode").
 		Bind("offline")
+
+	createWalletBuilder.Flag().Bool("validate", false,
+		"check vault, the named key and uphold are ready without generating keys or registering").
+		Bind("validate")
 }
 
 // CreateWallet creates a wallet
@@ -61,6 +68,14 @@ func CreateWallet(command *cobra.Command, args []string) error {
 	name := args[0]
 	logFile := name + "-registration.json"
 
+	validate, err := command.Flags().GetBool("validate")
+	if err != nil {
+		return err
+	}
+	if validate {
+		return ValidateCreateWallet(ctx, name, logFile, offline)
+	}
+
 	var state State
 	var enc *json.Encoder
 
@@ -140,28 +155,16 @@ func CreateWallet(command *cobra.Command, args []string) error {
 
 		wallet := uphold.Wallet{Info: state.WalletInfo, PrivKey: ed25519.PrivateKey{}, PubKey: publicKey}
 
-		err = wallet.SubmitRegistration(ctx, state.Registration)
+		resumed, err := registerWallet(ctx, logger, &wallet, name, state.Registration)
 		if err != nil {
 			return err
 		}
-
-		logger.Info().
-			Str("name", name).
-			Msg("success, registered new keypair and wallet")
 		logger.Info().
 			Str("card_id", wallet.Info.ProviderID).
+			Bool("resumed", resumed).
 			Msg("uphold")
 		state.WalletInfo.ProviderID = wallet.Info.ProviderID
 
-		depositAddr, err := wallet.CreateCardAddress(ctx, "ethereum")
-		if err != nil {
-			return err
-		}
-		logger.Info().
-			Str("address", depositAddr).
-			Str("currency", "ETH").
-			Msg("created deposit addr")
-
 		if offline {
 			err = enc.Encode(state)
 			if err != nil {
@@ -192,3 +195,116 @@ func CreateWallet(command *cobra.Command, args []string) error {
 	logger.Info().Msg("Wallet setup complete!")
 	return nil
 }
+
+// registerWallet submits the signed registration of the wallet and creates its deposit address. A card registered
+// by a previous attempt is resumed unchanged instead, so a retry neither registers a second card nor creates another
+// address. It returns true when an existing card was resumed
+func registerWallet(
+	ctx context.Context,
+	logger *zerolog.Logger,
+	wallet *uphold.Wallet,
+	name string,
+	registration string,
+) (bool, error) {
+	// a previous attempt may have submitted the registration before failing, don't register it twice
+	resumed, err := wallet.ResumeRegistration(ctx)
+	if err != nil {
+		return false, err
+	}
+	if resumed {
+		logger.Info().
+			Str("name", name).
+			Msg("wallet already registered, using existing card")
+		return true, nil
+	}
+
+	err = wallet.SubmitRegistration(ctx, registration)
+	if err != nil {
+		return false, err
+	}
+	logger.Info().
+		Str("name", name).
+		Msg("success, registered new keypair and wallet")
+
+	depositAddr, err := wallet.CreateCardAddress(ctx, "ethereum")
+	if err != nil {
+		return false, err
+	}
+	logger.Info().
+		Str("address", depositAddr).
+		Str("currency", "ETH").
+		Msg("created deposit addr")
+	return false, nil
+}
+
+// ValidateCreateWallet checks the prerequisites of CreateWallet without generating keys or registering
+// a checklist of the checks which passed and failed is printed, an error is returned if any check failed
+func ValidateCreateWallet(ctx context.Context, name, logFile string, offline bool) error {
+	var failed int
+	check := func(description string, err error) {
+		if err != nil {
+			failed++
+			fmt.Printf("[FAIL] %s: %s\n", description, err)
+			return
+		}
+		fmt.Printf("[PASS] %s\n", description)
+	}
+	skip := func(description, reason string) {
+		fmt.Printf("[SKIP] %s: %s\n", description, reason)
+	}
+
+	// an offline run resumes from the state recorded in the registration log
+	var state State
+	if offline {
+		f, err := os.Open(logFile)
+		if err == nil {
+			dec := json.NewDecoder(f)
+			for dec.More() && err == nil {
+				err = dec.Decode(&state)
+			}
+			_ = f.Close()
+		} else if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		check("read registration log "+logFile, err)
+	}
+	keyGenerated := len(state.WalletInfo.PublicKey) > 0 && len(state.Registration) > 0
+
+	wrappedClient, err := vaultsigner.Connect()
+	if err == nil {
+		_, err = wrappedClient.Client.Sys().ListMounts()
+	}
+	check("vault connectivity", err)
+
+	if err != nil {
+		skip("signing key "+name, "vault is unreachable")
+		skip("wallet record wallets/"+name, "vault is unreachable")
+	} else {
+		keys, err := wrappedClient.Client.Logical().Read("transit/keys/" + name)
+		if err == nil {
+			if keyGenerated && keys == nil {
+				err = errors.New("registration log has a keypair but the key does not exist")
+			} else if !keyGenerated && keys != nil {
+				err = errors.New("key already exists")
+			}
+		}
+		check("signing key "+name, err)
+
+		record, err := wrappedClient.Client.Logical().Read("wallets/" + name)
+		if err == nil && record != nil {
+			err = errors.New("wallet is already set up")
+		}
+		check("wallet record wallets/"+name, err)
+	}
+
+	if offline && !keyGenerated {
+		skip("uphold connectivity", "the offline machine only generates and signs the registration")
+	} else {
+		check("uphold connectivity", uphold.CheckConnectivity(ctx))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d checks failed", failed)
+	}
+	return nil
+}
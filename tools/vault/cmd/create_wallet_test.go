@@ -0,0 +1,116 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/brave-intl/bat-go/libs/altcurrency"
+	"github.com/brave-intl/bat-go/libs/httpsignature"
+	"github.com/brave-intl/bat-go/libs/logging"
+	"github.com/brave-intl/bat-go/libs/wallet"
+	"github.com/brave-intl/bat-go/libs/wallet/provider/uphold"
+	uuid "github.com/satori/go.uuid"
+)
+
+// newTestUpholdServer serves the card listing with cards and registers new cards as registered,
+// it counts the deposit addresses created
+func newTestUpholdServer(t *testing.T, cards *[]*uphold.CardDetails, registered uuid.UUID, addresses *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v0/me/cards":
+			body = *cards
+		case r.Method == http.MethodPost && r.URL.Path == "/v0/me/cards":
+			body = &uphold.CardDetails{ID: registered, Currency: altcurrency.BAT}
+		case r.Method == http.MethodPost && r.URL.Path == "/v0/me/cards/"+registered.String()+"/addresses":
+			*addresses++
+			body = map[string]string{"id": "0xdeposit"}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(body); err != nil {
+			t.Error(err)
+		}
+	}))
+}
+
+func newTestWallet(t *testing.T, base string) (*uphold.Wallet, string) {
+	publicKey, privateKey, err := httpsignature.GenerateEd25519Key(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bat := altcurrency.BAT
+	w := &uphold.Wallet{
+		Info:    wallet.Info{Provider: "uphold", AltCurrency: &bat, PublicKey: publicKey.String()},
+		PrivKey: privateKey,
+		PubKey:  publicKey,
+	}
+	uphold.WithBaseURL(base)(w)
+
+	registration, err := w.PrepareRegistration("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w, registration
+}
+
+func TestRegisterWalletResumesExistingCard(t *testing.T) {
+	ctx := context.Background()
+	_, logger := logging.SetupLogger(ctx)
+
+	existing := uuid.NewV4()
+	var cards []*uphold.CardDetails
+	addresses := 0
+	srv := newTestUpholdServer(t, &cards, existing, &addresses)
+	defer srv.Close()
+
+	// a previous attempt registered the card before failing
+	w, registration := newTestWallet(t, srv.URL)
+	cards = append(cards, &uphold.CardDetails{ID: existing, Currency: altcurrency.BAT, PublicKey: w.PubKey.String()})
+
+	resumed, err := registerWallet(ctx, logger, w, "test", registration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resumed {
+		t.Error("expected the existing card to be resumed")
+	}
+	if w.Info.ProviderID != existing.String() {
+		t.Errorf("expected card %s, found %s", existing, w.Info.ProviderID)
+	}
+	if addresses != 0 {
+		t.Errorf("expected no deposit address to be created for an existing card, %d were created", addresses)
+	}
+}
+
+func TestRegisterWalletCreatesAddressForNewCard(t *testing.T) {
+	ctx := context.Background()
+	_, logger := logging.SetupLogger(ctx)
+
+	registered := uuid.NewV4()
+	var cards []*uphold.CardDetails
+	addresses := 0
+	srv := newTestUpholdServer(t, &cards, registered, &addresses)
+	defer srv.Close()
+
+	w, registration := newTestWallet(t, srv.URL)
+
+	resumed, err := registerWallet(ctx, logger, w, "test", registration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed {
+		t.Error("expected a new card to be registered")
+	}
+	if w.Info.ProviderID != registered.String() {
+		t.Errorf("expected card %s, found %s", registered, w.Info.ProviderID)
+	}
+	if addresses != 1 {
+		t.Errorf("expected one deposit address to be created, %d were created", addresses)
+	}
+}
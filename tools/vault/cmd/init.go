@@ -3,12 +3,17 @@ package vault
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/user"
 	"path"
+	"sort"
+	"strings"
+	"time"
 
 	rootcmd "github.com/brave-intl/bat-go/cmd"
 
@@ -49,6 +54,64 @@ func init() {
 	initBuilder.Flag().Uint("key-threshold", 3,
 		"number of shares needed to unseal").
 		Bind("key-threshold")
+
+	initBuilder.Flag().String("out-dir", ".",
+		"the directory to write the share files and manifest to, created if it does not exist").
+		Bind("out-dir")
+
+	initBuilder.Flag().Bool("force", false,
+		"initialize even if the threshold leaves no redundancy or no security margin").
+		Bind("force")
+}
+
+// Manifest records how the vault was initialized and who holds the unseal shares
+type Manifest struct {
+	CreatedAt       time.Time           `json:"createdAt"`
+	SecretShares    uint                `json:"secretShares"`
+	SecretThreshold uint                `json:"secretThreshold"`
+	Recipients      []ManifestRecipient `json:"recipients"`
+}
+
+// ManifestRecipient is a holder of an unseal share
+type ManifestRecipient struct {
+	Identities  []string `json:"identities"`
+	Fingerprint string   `json:"fingerprint"`
+	ShareFile   string   `json:"shareFile"`
+}
+
+// newManifestRecipient describes the holder of the entity's share
+func newManifestRecipient(entity *openpgp.Entity, shareFile string) ManifestRecipient {
+	identities := []string{}
+	for name := range entity.Identities {
+		identities = append(identities, name)
+	}
+	sort.Strings(identities)
+	return ManifestRecipient{
+		Identities:  identities,
+		Fingerprint: strings.ToUpper(hex.EncodeToString(entity.PrimaryKey.Fingerprint[:])),
+		ShareFile:   shareFile,
+	}
+}
+
+// checkKeyThreshold validates the threshold for the number of shares, a threshold of 1 lets any single share
+// holder unseal and a threshold equal to the shares means losing any share loses the vault. these are only
+// allowed when forced and the returned warning should be shown
+func checkKeyThreshold(secretShares, secretThreshold uint, force bool) (string, error) {
+	if secretThreshold == 0 || secretThreshold > secretShares {
+		return "", fmt.Errorf("key-threshold must be between 1 and key-shares (%d)", secretShares)
+	}
+
+	var warning string
+	switch {
+	case secretThreshold == 1:
+		warning = "a key-threshold of 1 lets any single share holder unseal vault, there is no security margin"
+	case secretThreshold == secretShares:
+		warning = "a key-threshold equal to key-shares means losing any share makes vault impossible to unseal"
+	}
+	if warning != "" && !force {
+		return warning, errors.New(warning + ", pass --force to proceed")
+	}
+	return warning, nil
 }
 
 // Initialize initializes the vault server
@@ -56,9 +119,19 @@ func Initialize(command *cobra.Command, args []string) error {
 	gpgKeyFiles := args
 	secretShares := viper.GetUint("key-shares")
 	secretThreshold := viper.GetUint("key-threshold")
+	force := viper.GetBool("force")
+	outDir := viper.GetString("out-dir")
 	logger, err := appctx.GetLogger(command.Context())
 	cmdutils.Must(err)
 
+	warning, err := checkKeyThreshold(secretShares, secretThreshold, force)
+	if err != nil {
+		return err
+	}
+	if warning != "" {
+		logger.Warn().Msg(warning)
+	}
+
 	if len(gpgKeyFiles) == 0 {
 		return errors.New("a gpg file was not passed")
 	} else if len(gpgKeyFiles) != int(secretShares) {
@@ -104,6 +177,13 @@ func Initialize(command *cobra.Command, args []string) error {
 		gpgKeys = append(gpgKeys, base64.StdEncoding.EncodeToString(buf.Bytes()))
 	}
 
+	if len(outDir) > 0 {
+		err = os.MkdirAll(outDir, 0700)
+		if err != nil {
+			return err
+		}
+	}
+
 	wrappedClient, err := vaultsigner.Connect()
 	if err != nil {
 		return err
@@ -128,12 +208,19 @@ func Initialize(command *cobra.Command, args []string) error {
 
 	logger.Info().Msg("success, vault has been initialized")
 
+	manifest := Manifest{
+		CreatedAt:       time.Now().UTC(),
+		SecretShares:    secretShares,
+		SecretThreshold: secretThreshold,
+		Recipients:      []ManifestRecipient{},
+	}
+
 	if secretShares > 1 && secretThreshold == 1 {
 		// We need to encrypt the single returned share to all keys ourselves
 		key := resp.Keys[0]
 
 		logger.Info().Msgf("Writing share-0.gpg for all identities\n")
-		out, err := os.OpenFile("share-0.gpg", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		out, err := os.OpenFile(path.Join(outDir, "share-0.gpg"), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 		if err != nil {
 			return err
 		}
@@ -149,6 +236,10 @@ func Initialize(command *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
+
+		for _, entity := range entityList {
+			manifest.Recipients = append(manifest.Recipients, newManifestRecipient(entity, "share-0.gpg"))
+		}
 	} else {
 		// Vault has encrypted the shares for us
 		var b []byte
@@ -174,17 +265,30 @@ func Initialize(command *cobra.Command, args []string) error {
 						for k := range keys[0].Entity.Identities {
 							logger.Info().Msgf("Writing share-%d.gpg for %s\n", i, k)
 						}
+						manifest.Recipients = append(manifest.Recipients,
+							newManifestRecipient(keys[0].Entity, fmt.Sprintf("share-%d.gpg", i)))
 					}
 				}
 			}
 
-			err = ioutil.WriteFile(fmt.Sprintf("share-%d.gpg", i), b, 0600)
+			err = ioutil.WriteFile(path.Join(outDir, fmt.Sprintf("share-%d.gpg", i)), b, 0600)
 			if err != nil {
 				return err
 			}
 		}
 	}
 
+	// keep an auditable record of the shares for coordinating a later unseal
+	out, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(path.Join(outDir, "vault-manifest.json"), out, 0600)
+	if err != nil {
+		return err
+	}
+	logger.Info().Msg("Writing vault-manifest.json recording the share holders and threshold")
+
 	usr, err := user.Current()
 	if err != nil {
 		return err
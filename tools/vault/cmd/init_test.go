@@ -0,0 +1,33 @@
+package vault
+
+import "testing"
+
+func TestCheckKeyThreshold(t *testing.T) {
+	cases := []struct {
+		name      string
+		shares    uint
+		threshold uint
+		force     bool
+		warn      bool
+		err       bool
+	}{
+		{name: "redundant", shares: 5, threshold: 3},
+		{name: "single share", shares: 1, threshold: 1, warn: true, err: true},
+		{name: "single share forced", shares: 1, threshold: 1, force: true, warn: true},
+		{name: "threshold of 1", shares: 5, threshold: 1, warn: true, err: true},
+		{name: "threshold of 1 forced", shares: 5, threshold: 1, force: true, warn: true},
+		{name: "threshold equals shares", shares: 3, threshold: 3, warn: true, err: true},
+		{name: "threshold equals shares forced", shares: 3, threshold: 3, force: true, warn: true},
+		{name: "threshold of 0", shares: 3, threshold: 0, force: true, err: true},
+		{name: "threshold above shares", shares: 3, threshold: 4, force: true, err: true},
+	}
+	for _, c := range cases {
+		warning, err := checkKeyThreshold(c.shares, c.threshold, c.force)
+		if (warning != "") != c.warn {
+			t.Errorf("%s: unexpected warning %q", c.name, warning)
+		}
+		if (err != nil) != c.err {
+			t.Errorf("%s: unexpected error %v", c.name, err)
+		}
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	rootcmd "github.com/brave-intl/bat-go/cmd"
@@ -24,6 +28,7 @@ import (
 	bitflyersettlement "github.com/brave-intl/bat-go/tools/settlement/bitflyer"
 	settlementcmd "github.com/brave-intl/bat-go/tools/settlement/cmd"
 	geminisettlement "github.com/brave-intl/bat-go/tools/settlement/gemini"
+	paypalsettlement "github.com/brave-intl/bat-go/tools/settlement/paypal"
 	upholdsettlement "github.com/brave-intl/bat-go/tools/settlement/uphold"
 	vaultsigner "github.com/brave-intl/bat-go/tools/vault/signer"
 	"github.com/shopspring/decimal"
@@ -40,12 +45,8 @@ var (
 	}
 	// the combination of provider + transaction type gives you the key
 	// under which the vault secrets are located by default
-	providerTransactionTypes = map[string][]string{
-		"uphold":   {"contribution", "referral", "adsDirectDeposit"},
-		"paypal":   {"default"},
-		"gemini":   {"contribution", "referral", "adsDirectDeposit"},
-		"bitflyer": {"default"},
-	}
+	providerTransactionTypes = settlement.ProviderTransactionTypes
+
 	artifactGenerators = map[string]func(
 		context.Context,
 		string,
@@ -123,6 +124,26 @@ func init() {
 	signSettlementBuilder.Flag().Int("chunk-size", 0,
 		"how many transfers to combine per request, 0 indicates the default value").
 		Bind("chunk-size")
+
+	signSettlementBuilder.Flag().Int("gemini-batch-size", geminisettlement.DefaultBatchSize,
+		fmt.Sprintf("how many payouts to sign into each gemini bulk payout, between 1 and %d", geminisettlement.MaxBatchSize)).
+		Bind("gemini-batch-size")
+
+	signSettlementBuilder.Flag().Bool("json", false,
+		"print the summary of signed files as json").
+		Bind("json")
+
+	signSettlementBuilder.Flag().Bool("preview", false,
+		"print the transactions, BAT totals and estimated fees per file without signing").
+		Bind("preview")
+
+	signSettlementBuilder.Flag().Bool("check-destinations", false,
+		"also refuse settlements which pay different channels to the same destination").
+		Bind("check-destinations")
+
+	signSettlementBuilder.Flag().StringSlice("resign-from", []string{},
+		"logs or outputs of a prior upload, only transactions which are missing from them or failed are signed").
+		Bind("resign-from")
 }
 
 // SignSettlement runs the signing of a settlement
@@ -153,6 +174,30 @@ func SignSettlement(command *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	var opts signSettlementOptions
+	opts.JSON, err = command.Flags().GetBool("json")
+	if err != nil {
+		return err
+	}
+	opts.Preview, err = command.Flags().GetBool("preview")
+	if err != nil {
+		return err
+	}
+	resignFrom, err := command.Flags().GetStringSlice("resign-from")
+	if err != nil {
+		return err
+	}
+	checkDestinations, err := command.Flags().GetBool("check-destinations")
+	if err != nil {
+		return err
+	}
+	geminiBatchSize, err := command.Flags().GetInt("gemini-batch-size")
+	if err != nil {
+		return err
+	}
+	if err := geminisettlement.ValidateBatchSize(geminiBatchSize); err != nil {
+		return err
+	}
 
 	logger, err := appctx.GetLogger(command.Context())
 	if err != nil {
@@ -168,6 +213,24 @@ func SignSettlement(command *cobra.Command, args []string) error {
 		}
 	}
 
+	signedSuffix := "-signed.json"
+	var submitted []custodian.Transaction
+	if len(resignFrom) > 0 {
+		if mergeCustodial {
+			// merged payouts are logged under a single channel, so the rest would be signed again
+			return errors.New("resign-from cannot be combined with merge-custodial")
+		}
+		for _, submitLog := range resignFrom {
+			txs, err := settlement.ReadSubmitLog(submitLog)
+			if err != nil {
+				return err
+			}
+			submitted = append(submitted, txs...)
+		}
+		logger.Info().Int("len(submitted)", len(submitted)).Msg("read prior submission")
+		signedSuffix = "-resigned.json"
+	}
+
 	var mergedSettlements []settlement.AntifraudTransaction
 
 	for _, inputFile := range inputFiles {
@@ -176,7 +239,7 @@ func SignSettlement(command *cobra.Command, args []string) error {
 		sublog.Info().Msg("reading settlement file")
 
 		// append -signed to the filename
-		outBaseFile := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile)) + "-signed.json"
+		outBaseFile := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile)) + signedSuffix
 
 		// all settlements file
 		settlementJSON, err := ioutil.ReadFile(inputFile)
@@ -192,6 +255,21 @@ func SignSettlement(command *cobra.Command, args []string) error {
 
 		sublog.Info().Int("len(antifraudSettlements)", len(antifraudSettlements)).Msg("deserialized settlement file")
 
+		if len(resignFrom) > 0 {
+			unsettled, inFlight := settlement.FilterUnsettled(antifraudSettlements, submitted)
+			for _, tx := range inFlight {
+				sublog.Warn().
+					Str("channel", tx.Channel).
+					Str("status", tx.Status).
+					Msg("not signing transaction which may still settle")
+			}
+			sublog.Info().
+				Int("len(unsettled)", len(unsettled)).
+				Int("len(inFlight)", len(inFlight)).
+				Msg("filtered out settled transactions")
+			antifraudSettlements = unsettled
+		}
+
 		mergedSettlements = append(mergedSettlements, antifraudSettlements...)
 		if merge {
 			sublog.Info().Int("len(mergedSettlements)", len(mergedSettlements)).Msg("merged settlements")
@@ -208,23 +286,51 @@ func SignSettlement(command *cobra.Command, args []string) error {
 				outDir,
 				outBaseFile,
 				antifraudSettlements,
+				opts,
 			)
 		}
 	}
 
-	err = settlement.CheckForDuplicates(mergedSettlements)
+	err = settlement.CheckForDuplicates(mergedSettlements, checkDestinations)
 	if err != nil {
 		return err
 	}
 
 	if merge {
 		logger.Info().Int("len(mergedSettlements)", len(mergedSettlements)).Msg("processing merged settlements")
-		return processSettlements(command.Context(), providers, outDir, "merged-signed.json", mergedSettlements)
+		return processSettlements(command.Context(), providers, outDir, "merged"+signedSuffix, mergedSettlements, opts)
 	}
 	return nil
 }
 
-func processSettlements(ctx context.Context, providers []string, outDir string, outBaseFile string, antifraudSettlements []settlement.AntifraudTransaction) error {
+// signSettlementOptions control the output of processSettlements
+type signSettlementOptions struct {
+	// JSON prints the summary as json
+	JSON bool
+	// Preview summarizes the settlements without signing them
+	Preview bool
+}
+
+// SignedArtifact summarizes the settlements signed into a single output file
+type SignedArtifact struct {
+	Provider     string          `json:"provider"`
+	TxType       string          `json:"txType"`
+	Transactions int             `json:"transactions"`
+	TotalBAT     decimal.Decimal `json:"totalBat"`
+	// EstimatedFeeBAT is approximate, see FeeSource for how it was estimated
+	EstimatedFeeBAT decimal.Decimal `json:"estimatedFeeBat"`
+	FeeSource       string          `json:"feeSource"`
+	Output          string          `json:"output"`
+}
+
+func processSettlements(
+	ctx context.Context,
+	providers []string,
+	outDir string,
+	outBaseFile string,
+	antifraudSettlements []settlement.AntifraudTransaction,
+	opts signSettlementOptions,
+) error {
 	logger, err := appctx.GetLogger(ctx)
 	if err != nil {
 		return err
@@ -244,11 +350,15 @@ func processSettlements(ctx context.Context, providers []string, outDir string,
 	}
 	logLine.Msg("split settlements by provider and transaction type")
 
-	wrappedClient, err := vaultsigner.Connect()
-	if err != nil {
-		return err
+	var wrappedClient *vaultsigner.WrappedClient
+	if !opts.Preview {
+		wrappedClient, err = vaultsigner.Connect()
+		if err != nil {
+			return err
+		}
 	}
 
+	var artifacts []SignedArtifact
 	for _, provider := range providers {
 		for _, txType := range providerTransactionTypes[provider] {
 			walletKey := provider + "-" + txType
@@ -267,6 +377,25 @@ func processSettlements(ctx context.Context, providers []string, outDir string,
 				Int("settlements", len(settlements)).
 				Logger()
 
+			totalBAT := decimal.Zero
+			for _, tx := range settlements {
+				totalBAT = totalBAT.Add(altcurrency.BAT.FromProbi(tx.Probi))
+			}
+			feeSchedule := Config.FeeSchedule(provider)
+			artifact := SignedArtifact{
+				Provider:        provider,
+				TxType:          txType,
+				Transactions:    len(settlements),
+				TotalBAT:        totalBAT,
+				EstimatedFeeBAT: feeSchedule.EstimateFees(settlements),
+				FeeSource:       feeSchedule.Source,
+			}
+
+			if opts.Preview {
+				artifacts = append(artifacts, artifact)
+				continue
+			}
+
 			sublog.Info().Str("wallet", secretKey).Msg("attempting to sign settlements")
 
 			err := artifactGenerators[provider](
@@ -281,7 +410,63 @@ func processSettlements(ctx context.Context, providers []string, outDir string,
 				return err
 			}
 			sublog.Info().Msg("created artifact")
+
+			artifact.Output = output
+			artifacts = append(artifacts, artifact)
+		}
+	}
+	return printSignSummary(os.Stdout, artifacts, opts)
+}
+
+// printSignSummary writes one line per signed file, or the artifacts as a json array for automation
+// fee estimates are approximate, the source of each estimate is listed after the table
+func printSignSummary(w io.Writer, artifacts []SignedArtifact, opts signSettlementOptions) error {
+	if opts.JSON {
+		if artifacts == nil {
+			artifacts = []SignedArtifact{}
 		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(artifacts)
+	}
+
+	if opts.Preview {
+		fmt.Fprintln(w, "preview, no settlements were signed")
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tTYPE\tTRANSACTIONS\tTOTAL BAT\tEST. FEE BAT\tOUTPUT")
+	var (
+		transactions int
+		totalBAT     = decimal.Zero
+		totalFeeBAT  = decimal.Zero
+		feeSources   = map[string]string{}
+	)
+	for _, artifact := range artifacts {
+		output := artifact.Output
+		if output == "" {
+			output = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t~%s\t%s\n",
+			artifact.Provider, artifact.TxType, artifact.Transactions, artifact.TotalBAT,
+			artifact.EstimatedFeeBAT.StringFixed(8), output)
+		transactions += artifact.Transactions
+		totalBAT = totalBAT.Add(artifact.TotalBAT)
+		totalFeeBAT = totalFeeBAT.Add(artifact.EstimatedFeeBAT)
+		feeSources[artifact.Provider] = artifact.FeeSource
+	}
+	fmt.Fprintf(tw, "total\t\t%d\t%s\t~%s\t\n", transactions, totalBAT, totalFeeBAT.StringFixed(8))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	providers := make([]string, 0, len(feeSources))
+	for provider := range feeSources {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	for _, provider := range providers {
+		fmt.Fprintf(w, "estimated %s fees: %s\n", provider, feeSources[provider])
 	}
 	return nil
 }
@@ -449,8 +634,9 @@ func createGeminiArtifact(
 		return err
 	}
 	oauthClientID := response.Data["clientid"].(string)
-	// group transactions (500 at a time)
-	privatePayloads, err := geminisettlement.TransformTransactions(ctx, oauthClientID, geminiOnlySettlements)
+	// group transactions into bulk payouts of the configured size
+	batchSize := viper.GetViper().GetInt("gemini-batch-size")
+	privatePayloads, err := geminisettlement.TransformTransactionsN(ctx, oauthClientID, geminiOnlySettlements, batchSize)
 	if err != nil {
 		return err
 	}
@@ -491,12 +677,21 @@ func signGeminiRequests(
 	if err != nil {
 		return nil, err
 	}
-	return geminisettlement.SignRequests(
+	signedRequests, err := geminisettlement.SignRequests(
 		clientID,
 		clientKey,
 		hmacSecret,
 		privateRequests,
 	)
+	if err != nil {
+		return nil, err
+	}
+	// each request is signed for a range of nonces, overlapping ranges would fail at gemini
+	err = geminisettlement.CheckNonceCollisions(*signedRequests)
+	if err != nil {
+		return nil, fmt.Errorf("wallet %s: %w", walletKey, err)
+	}
+	return signedRequests, nil
 }
 
 func createPaypalArtifact(
@@ -509,8 +704,10 @@ func createPaypalArtifact(
 	return settlementcmd.PaypalTransformForMassPay(
 		ctx,
 		&paypalOnlySettlements,
+		nil,
 		"JPY",
 		decimal.NewFromFloat(viper.GetFloat64("jpyrate")),
 		outputFile,
+		paypalsettlement.DefaultLimits("JPY"),
 	)
 }
@@ -0,0 +1,214 @@
+package vault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	cmdutils "github.com/brave-intl/bat-go/cmd"
+	rootcmd "github.com/brave-intl/bat-go/cmd"
+	"github.com/brave-intl/bat-go/libs/altcurrency"
+	logutils "github.com/brave-intl/bat-go/libs/logging"
+	"github.com/brave-intl/bat-go/libs/wallet"
+	vaultsigner "github.com/brave-intl/bat-go/tools/vault/signer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// ExportWalletCmd exports the non-secret metadata of a vault wallet
+	ExportWalletCmd = &cobra.Command{
+		Use:   "export-wallet WALLET_NAME",
+		Short: "exports the public metadata of a vault wallet, private keys are never exported",
+		Args:  cobra.ExactArgs(1),
+		Run:   rootcmd.Perform("export wallet", ExportWallet),
+	}
+
+	// ImportWalletCmd imports the non-secret metadata of a wallet into vault
+	ImportWalletCmd = &cobra.Command{
+		Use:   "import-wallet WALLET_NAME",
+		Short: "imports wallet metadata exported by export-wallet, the signing key must be imported separately",
+		Args:  cobra.ExactArgs(1),
+		Run:   rootcmd.Perform("import wallet", ImportWallet),
+	}
+)
+
+func init() {
+	VaultCmd.AddCommand(
+		ExportWalletCmd,
+		ImportWalletCmd,
+	)
+
+	exportWalletBuilder := cmdutils.NewFlagBuilder(ExportWalletCmd)
+	exportWalletBuilder.Flag().String("out", "",
+		"the file to write the wallet metadata to, defaults to WALLET_NAME-wallet-info.json").
+		Bind("out")
+
+	importWalletBuilder := cmdutils.NewFlagBuilder(ImportWalletCmd)
+	importWalletBuilder.Flag().String("in", "",
+		"the file produced by export-wallet to read the wallet metadata from").
+		Bind("in").
+		Require()
+	importWalletBuilder.Flag().Bool("force", false,
+		"replace an existing wallet of the same name with a different provider id").
+		Bind("force")
+}
+
+// ExportWallet writes the metadata of a vault wallet to a json file
+// only the provider, provider id, public key and altcurrency are exported. The private key is
+// held by the vault transit backend and is never read or written by this command
+func ExportWallet(command *cobra.Command, args []string) error {
+	ctx := command.Context()
+	_, logger := logutils.SetupLogger(ctx)
+
+	name := args[0]
+	out, err := command.Flags().GetString("out")
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		out = name + "-wallet-info.json"
+	}
+
+	wrappedClient, err := vaultsigner.Connect()
+	if err != nil {
+		return err
+	}
+
+	response, err := wrappedClient.Client.Logical().Read("wallets/" + name)
+	if err != nil {
+		return err
+	}
+	if response == nil {
+		return fmt.Errorf("no wallet named %s", name)
+	}
+	providerID, ok := response.Data["providerId"].(string)
+	if !ok {
+		return errors.New("invalid wallet name")
+	}
+
+	keys, err := wrappedClient.Client.Logical().Read("transit/keys/" + name)
+	if err != nil {
+		return err
+	}
+	if keys == nil {
+		return fmt.Errorf("no signing key named %s", name)
+	}
+	signer, err := wrappedClient.GetEd25519Signer(name)
+	if err != nil {
+		return err
+	}
+
+	info := wallet.Info{
+		Provider:   "uphold",
+		ProviderID: providerID,
+		PublicKey:  signer.String(),
+	}
+	{
+		tmp := altcurrency.BAT
+		info.AltCurrency = &tmp
+	}
+
+	b, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, b, 0600); err != nil {
+		return err
+	}
+
+	logger.Info().
+		Str("name", name).
+		Str("provider_id", info.ProviderID).
+		Str("public_key", info.PublicKey).
+		Str("out", out).
+		Msg("exported wallet metadata")
+	return nil
+}
+
+// ImportWallet writes wallet metadata exported by ExportWallet into the wallets path of vault
+// no secret material is imported, the matching signing key must be imported with import-key
+func ImportWallet(command *cobra.Command, args []string) error {
+	ctx := command.Context()
+	_, logger := logutils.SetupLogger(ctx)
+
+	name := args[0]
+	in, err := command.Flags().GetString("in")
+	if err != nil {
+		return err
+	}
+	force, err := command.Flags().GetBool("force")
+	if err != nil {
+		return err
+	}
+
+	b, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+	var info wallet.Info
+	if err := json.Unmarshal(b, &info); err != nil {
+		return fmt.Errorf("failed to read wallet metadata: %w", err)
+	}
+	if info.Provider != "uphold" || info.ProviderID == "" {
+		return errors.New("wallet metadata must include an uphold provider id")
+	}
+
+	wrappedClient, err := vaultsigner.Connect()
+	if err != nil {
+		return err
+	}
+	if err := wrappedClient.GenerateMounts(); err != nil {
+		return err
+	}
+
+	// the wallet is only usable once its signing key exists, check it matches when present
+	keys, err := wrappedClient.Client.Logical().Read("transit/keys/" + name)
+	if err != nil {
+		return err
+	}
+	if keys == nil {
+		logger.Warn().
+			Str("name", name).
+			Msg("no signing key for wallet, import it with import-key before use")
+	} else {
+		signer, err := wrappedClient.GetEd25519Signer(name)
+		if err != nil {
+			return err
+		}
+		if info.PublicKey != "" && signer.String() != info.PublicKey {
+			return fmt.Errorf("signing key %s does not match the exported public key", name)
+		}
+	}
+
+	// a wallet of the same name may already point at another card, never replace it by accident
+	existing, err := wrappedClient.Client.Logical().Read("wallets/" + name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		providerID, _ := existing.Data["providerId"].(string)
+		if providerID != info.ProviderID {
+			if !force {
+				return fmt.Errorf("wallet %s already exists with provider id %s, pass --force to replace it", name, providerID)
+			}
+			logger.Warn().
+				Str("name", name).
+				Str("provider_id", providerID).
+				Msg("replacing existing wallet")
+		}
+	}
+
+	_, err = wrappedClient.Client.Logical().Write("wallets/"+name, map[string]interface{}{
+		"providerId": info.ProviderID,
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info().
+		Str("name", name).
+		Str("provider_id", info.ProviderID).
+		Msg("imported wallet metadata")
+	return nil
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	cmdutils "github.com/brave-intl/bat-go/cmd"
+	rootcmd "github.com/brave-intl/bat-go/cmd"
+	"github.com/brave-intl/bat-go/libs/altcurrency"
+	appctx "github.com/brave-intl/bat-go/libs/context"
+	"github.com/brave-intl/bat-go/libs/logging"
+	"github.com/brave-intl/bat-go/libs/wallet"
+	"github.com/brave-intl/bat-go/libs/wallet/provider"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// BalanceSnapshotCmd records the balances of a set of wallets
+	BalanceSnapshotCmd = &cobra.Command{
+		Use:   "balance-snapshot",
+		Short: "records a timestamped snapshot of the balances of a set of wallets",
+		Run:   rootcmd.Perform("balance snapshot", RunBalanceSnapshot),
+	}
+)
+
+func init() {
+	WalletsCmd.AddCommand(BalanceSnapshotCmd)
+
+	balanceSnapshotBuilder := cmdutils.NewFlagBuilder(BalanceSnapshotCmd)
+
+	balanceSnapshotBuilder.Flag().String("in", "",
+		"file containing the provider ids of the wallets to snapshot, one per line").
+		Bind("in").
+		Require()
+
+	balanceSnapshotBuilder.Flag().String("out", "",
+		"json lines file to append snapshots to, snapshots are written to stdout if not set").
+		Bind("out")
+
+	balanceSnapshotBuilder.Flag().String("provider", "uphold",
+		"provider of the wallets").
+		Bind("provider")
+
+	balanceSnapshotBuilder.Flag().Int("workers", 4,
+		"number of concurrent balance requests").
+		Bind("workers")
+
+	balanceSnapshotBuilder.Flag().Duration("interval", 200*time.Millisecond,
+		"minimum time between balance requests to the provider").
+		Bind("interval")
+
+	balanceSnapshotBuilder.Flag().Duration("every", 0,
+		"take a snapshot at this period until interrupted, a single snapshot is taken if not set").
+		Bind("every")
+}
+
+// BalanceSnapshot is the balance of a single wallet at a point in time
+type BalanceSnapshot struct {
+	Time             time.Time        `json:"time"`
+	Provider         string           `json:"provider"`
+	ProviderID       string           `json:"providerId"`
+	TotalProbi       *decimal.Decimal `json:"totalProbi,omitempty"`
+	SpendableProbi   *decimal.Decimal `json:"spendableProbi,omitempty"`
+	ConfirmedProbi   *decimal.Decimal `json:"confirmedProbi,omitempty"`
+	UnconfirmedProbi *decimal.Decimal `json:"unconfirmedProbi,omitempty"`
+	Error            string           `json:"error,omitempty"`
+}
+
+// RunBalanceSnapshot runs the balance snapshot command
+func RunBalanceSnapshot(cmd *cobra.Command, args []string) error {
+	in, err := cmd.Flags().GetString("in")
+	if err != nil {
+		return err
+	}
+	out, err := cmd.Flags().GetString("out")
+	if err != nil {
+		return err
+	}
+	walletProvider, err := cmd.Flags().GetString("provider")
+	if err != nil {
+		return err
+	}
+	workers, err := cmd.Flags().GetInt("workers")
+	if err != nil {
+		return err
+	}
+	interval, err := cmd.Flags().GetDuration("interval")
+	if err != nil {
+		return err
+	}
+	every, err := cmd.Flags().GetDuration("every")
+	if err != nil {
+		return err
+	}
+	if workers < 1 {
+		return errors.New("workers must be at least 1")
+	}
+	if interval <= 0 {
+		return errors.New("interval must be greater than 0")
+	}
+	if every < 0 {
+		return errors.New("every must not be negative")
+	}
+
+	// provider ids are read in the same format as wallet names for create-batch
+	providerIDs, err := readBatchNames(in)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		f, err := os.OpenFile(out, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	ctx := cmd.Context()
+	if every == 0 {
+		return SnapshotBalances(ctx, w, walletProvider, providerIDs, workers, interval)
+	}
+
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		if err := SnapshotBalances(ctx, w, walletProvider, providerIDs, workers, interval); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// SnapshotBalances fetches the current balance of each wallet and writes one json line per wallet to w
+// all lines of a snapshot share the same timestamp, wallets whose balance cannot be fetched are
+// recorded with the error so that gaps are visible in the series
+func SnapshotBalances(
+	ctx context.Context,
+	w io.Writer,
+	walletProvider string,
+	providerIDs []string,
+	workers int,
+	interval time.Duration,
+) error {
+	logger, lerr := appctx.GetLogger(ctx)
+	if lerr != nil {
+		_, logger = logging.SetupLogger(ctx)
+	}
+
+	var (
+		mu       sync.Mutex
+		enc      = json.NewEncoder(w)
+		writeErr error
+		failed   int
+		now      = time.Now().UTC()
+	)
+
+	record := func(snapshot BalanceSnapshot) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if snapshot.Error != "" {
+			failed++
+		}
+		if err := enc.Encode(snapshot); err != nil && writeErr == nil {
+			writeErr = err
+		}
+	}
+
+	newSnapshot := func(providerID string) BalanceSnapshot {
+		return BalanceSnapshot{
+			Time:       now,
+			Provider:   walletProvider,
+			ProviderID: providerID,
+		}
+	}
+
+	forEachThrottled(ctx, providerIDs, workers, interval,
+		func(providerID string) {
+			snapshot := newSnapshot(providerID)
+			balance, err := fetchBalance(ctx, walletProvider, providerID)
+			if err != nil {
+				logger.Error().Err(err).Str("provider_id", providerID).Msg("failed to fetch balance")
+				snapshot.Error = err.Error()
+				record(snapshot)
+				return
+			}
+
+			snapshot.TotalProbi = &balance.TotalProbi
+			snapshot.SpendableProbi = &balance.SpendableProbi
+			snapshot.ConfirmedProbi = &balance.ConfirmedProbi
+			snapshot.UnconfirmedProbi = &balance.UnconfirmedProbi
+			record(snapshot)
+		},
+		func(providerID string, err error) {
+			snapshot := newSnapshot(providerID)
+			snapshot.Error = err.Error()
+			record(snapshot)
+		},
+	)
+
+	logger.Info().
+		Time("time", now).
+		Int("wallets", len(providerIDs)).
+		Int("failed", failed).
+		Msg("balance snapshot complete")
+
+	return writeErr
+}
+
+func fetchBalance(ctx context.Context, walletProvider, providerID string) (*wallet.Balance, error) {
+	walletc := altcurrency.BAT
+	info := wallet.Info{
+		Provider:    walletProvider,
+		ProviderID:  providerID,
+		AltCurrency: &walletc,
+	}
+	w, err := provider.GetWallet(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	return w.GetBalance(ctx, true)
+}
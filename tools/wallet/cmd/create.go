@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/hex"
 	"fmt"
 
@@ -39,6 +40,11 @@ func init() {
 	createBuilder.Flag().String("provider", "",
 		"the provider for the wallet").
 		Bind("provider")
+
+	// public-key - the key of an earlier attempt whose registration may have gone through
+	createBuilder.Flag().String("public-key", "",
+		"the hex encoded public key from an earlier attempt to create this wallet, reused if already registered").
+		Bind("public-key")
 }
 
 // Create creates a wallet
@@ -54,45 +60,61 @@ func Create(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
+		publicKey, err := cmd.Flags().GetString("public-key")
+		if err != nil {
+			return err
+		}
 		return CreateOnUphold(
 			cmd.Context(),
 			name,
+			publicKey,
 		)
 	}
 	return nil
 }
 
-// CreateOnUphold creates a wallet on uphold
-func CreateOnUphold(ctx context.Context, name string) error {
+// CreateOnUphold creates a wallet on uphold, if publicKeyHex is set and a previous attempt already registered
+// that key the existing card is reported instead of registering a new one
+func CreateOnUphold(ctx context.Context, name string, publicKeyHex string) error {
 	logger, lerr := appctx.GetLogger(ctx)
 	if lerr != nil {
 		_, logger = logging.SetupLogger(ctx)
 	}
 
-	publicKey, privateKey, err := httpsignature.GenerateEd25519Key(nil)
+	if publicKeyHex != "" {
+		publicKey, err := hex.DecodeString(publicKeyHex)
+		if err != nil {
+			return fmt.Errorf("failed to decode public key: %w", err)
+		}
+		existing := &uphold.Wallet{PubKey: httpsignature.Ed25519PubKey(publicKey)}
+
+		// a previous attempt may have registered the wallet before failing, don't register it twice
+		registered, err := existing.ResumeRegistration(ctx)
+		if err != nil {
+			return err
+		}
+		if registered {
+			logger.Info().
+				Str("name", name).
+				Str("public_key", publicKeyHex).
+				Str("provider_id", existing.Info.ProviderID).
+				Msg("wallet already registered, using existing Uphold card ID")
+			return nil
+		}
+	}
+
+	// only generate a keypair once we know there is no card to resume
+	wallet, privateKeyHex, err := newUpholdWallet()
 	if err != nil {
 		return err
 	}
-	publicKeyHex := hex.EncodeToString([]byte(publicKey))
 
-	privateKeyHex := hex.EncodeToString([]byte(privateKey))
 	logger.Info().
-		Str("public_key", publicKeyHex).
+		Str("public_key", wallet.Info.PublicKey).
 		Str("private_key", privateKeyHex).
 		Str("name", name).
 		Msg("key created")
 
-	var info wallet.Info
-	info.Provider = "uphold"
-	info.ProviderID = ""
-	{
-		tmp := altcurrency.BAT
-		info.AltCurrency = &tmp
-	}
-	info.PublicKey = publicKeyHex
-
-	wallet := &uphold.Wallet{Info: info, PrivKey: privateKey, PubKey: publicKey}
-
 	err = wallet.Register(ctx, name)
 	if err != nil {
 		return err
@@ -103,3 +125,40 @@ func CreateOnUphold(ctx context.Context, name string) error {
 		Msg("Uphold card ID")
 	return nil
 }
+
+// newUpholdWallet generates a new keypair for an unregistered uphold wallet, returning the hex encoded private key
+func newUpholdWallet() (*uphold.Wallet, string, error) {
+	_, privateKey, err := httpsignature.GenerateEd25519Key(nil)
+	if err != nil {
+		return nil, "", err
+	}
+	return upholdWalletFromKey(privateKey), hex.EncodeToString([]byte(privateKey)), nil
+}
+
+// upholdWalletFromPrivateKeyHex returns the uphold wallet for a hex encoded private key generated by an earlier attempt
+func upholdWalletFromPrivateKeyHex(privateKeyHex string) (*uphold.Wallet, error) {
+	privateKey, err := hex.DecodeString(privateKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode private key: %w", err)
+	}
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("private key must be %d bytes", ed25519.PrivateKeySize)
+	}
+	return upholdWalletFromKey(ed25519.PrivateKey(privateKey)), nil
+}
+
+// upholdWalletFromKey returns an unregistered uphold wallet for the keypair of privateKey
+func upholdWalletFromKey(privateKey ed25519.PrivateKey) *uphold.Wallet {
+	publicKey := httpsignature.Ed25519PubKey(privateKey.Public().(ed25519.PublicKey))
+
+	var info wallet.Info
+	info.Provider = "uphold"
+	info.ProviderID = ""
+	{
+		tmp := altcurrency.BAT
+		info.AltCurrency = &tmp
+	}
+	info.PublicKey = hex.EncodeToString([]byte(publicKey))
+
+	return &uphold.Wallet{Info: info, PrivKey: privateKey, PubKey: publicKey}
+}
@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	cmdutils "github.com/brave-intl/bat-go/cmd"
+	rootcmd "github.com/brave-intl/bat-go/cmd"
+	appctx "github.com/brave-intl/bat-go/libs/context"
+	"github.com/brave-intl/bat-go/libs/logging"
+	"github.com/brave-intl/bat-go/libs/wallet/provider/uphold"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// CreateBatchCmd registers many wallets on uphold
+	CreateBatchCmd = &cobra.Command{
+		Use:   "create-batch",
+		Short: "creates a batch of wallets on uphold",
+		Run:   rootcmd.Perform("create batch", RunCreateBatch),
+	}
+)
+
+func init() {
+	WalletsCmd.AddCommand(CreateBatchCmd)
+
+	createBatchBuilder := cmdutils.NewFlagBuilder(CreateBatchCmd)
+
+	createBatchBuilder.Flag().String("in", "",
+		"file containing the names of the wallets to create, one per line").
+		Bind("in").
+		Require()
+
+	createBatchBuilder.Flag().String("out", "wallets.json",
+		"results file, wallets already registered in this file are skipped on re-run").
+		Bind("out")
+
+	createBatchBuilder.Flag().Int("workers", 4,
+		"number of concurrent registrations").
+		Bind("workers")
+
+	createBatchBuilder.Flag().Duration("interval", 500*time.Millisecond,
+		"minimum time between registration requests to uphold").
+		Bind("interval")
+}
+
+// BatchResult is the outcome of registering a single wallet in a batch
+// the private key is written to the results file, as it is not stored anywhere else. Failed attempts
+// record their key too, so that a re-run can resume a card uphold created despite the failure
+type BatchResult struct {
+	Name       string `json:"name"`
+	ProviderID string `json:"providerId,omitempty"`
+	PublicKey  string `json:"publicKey,omitempty"`
+	PrivateKey string `json:"privateKey,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// RunCreateBatch registers a batch of wallets on uphold
+func RunCreateBatch(cmd *cobra.Command, args []string) error {
+	in, err := cmd.Flags().GetString("in")
+	if err != nil {
+		return err
+	}
+	out, err := cmd.Flags().GetString("out")
+	if err != nil {
+		return err
+	}
+	workers, err := cmd.Flags().GetInt("workers")
+	if err != nil {
+		return err
+	}
+	interval, err := cmd.Flags().GetDuration("interval")
+	if err != nil {
+		return err
+	}
+	if workers < 1 {
+		return errors.New("workers must be at least 1")
+	}
+	if interval <= 0 {
+		return errors.New("interval must be greater than 0")
+	}
+
+	names, err := readBatchNames(in)
+	if err != nil {
+		return err
+	}
+	registered, err := readBatchResults(out)
+	if err != nil {
+		return err
+	}
+
+	return CreateBatchOnUphold(cmd.Context(), names, registered, out, workers, interval)
+}
+
+// CreateBatchOnUphold concurrently registers the named wallets on uphold, skipping those already registered
+// results are appended to the file at out as each registration completes so an interrupted batch can be resumed
+func CreateBatchOnUphold(
+	ctx context.Context,
+	names []string,
+	registered map[string]BatchResult,
+	out string,
+	workers int,
+	interval time.Duration,
+) error {
+	logger, lerr := appctx.GetLogger(ctx)
+	if lerr != nil {
+		_, logger = logging.SetupLogger(ctx)
+	}
+
+	f, err := os.OpenFile(out, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			logger.Error().Err(err).Msg("failed to close results file")
+		}
+	}()
+
+	var (
+		mu      sync.Mutex
+		enc     = json.NewEncoder(f)
+		failed  []string
+		created int
+	)
+
+	record := func(result BatchResult) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err := enc.Encode(result); err != nil {
+			// the wallet may exist on uphold but cannot be recorded, make sure the operator sees which one
+			// without writing its key material to the logs
+			logger.Error().Err(err).
+				Str("name", result.Name).
+				Str("provider_id", result.ProviderID).
+				Msg("failed to write result")
+		}
+		if result.Error != "" {
+			failed = append(failed, result.Name)
+			return
+		}
+		created++
+	}
+
+	var unregistered []string
+	skipped := 0
+	for _, name := range names {
+		if r, ok := registered[name]; ok && r.ProviderID != "" {
+			skipped++
+			continue
+		}
+		unregistered = append(unregistered, name)
+	}
+
+	forEachThrottled(ctx, unregistered, workers, interval,
+		func(name string) {
+			result := BatchResult{Name: name}
+			wallet, privateKeyHex, resumed, err := registerBatchWallet(ctx, name, registered[name])
+			if wallet != nil {
+				// the key is kept even if registration fails, as uphold may have created the card regardless
+				result.PublicKey = wallet.Info.PublicKey
+				result.PrivateKey = privateKeyHex
+			}
+			if err != nil {
+				logger.Error().Err(err).Str("name", name).Msg("failed to register wallet")
+				result.Error = err.Error()
+				record(result)
+				return
+			}
+
+			result.ProviderID = wallet.Info.ProviderID
+			logger.Info().
+				Str("name", name).
+				Str("provider_id", result.ProviderID).
+				Bool("resumed", resumed).
+				Msg("registered wallet")
+			record(result)
+		},
+		func(name string, err error) {
+			record(BatchResult{Name: name, Error: err.Error()})
+		},
+	)
+
+	logger.Info().
+		Int("created", created).
+		Int("skipped", skipped).
+		Int("failed", len(failed)).
+		Str("out", out).
+		Msg("batch complete")
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to register %d wallets, re-run to retry: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// registerBatchWallet registers the named wallet, reusing the key of a previous failed attempt if there is one
+// so that a card uphold created for that attempt is resumed rather than orphaned by registering a new key
+func registerBatchWallet(
+	ctx context.Context,
+	name string,
+	previous BatchResult,
+) (*uphold.Wallet, string, bool, error) {
+	if previous.PrivateKey == "" {
+		wallet, privateKeyHex, err := newUpholdWallet()
+		if err != nil {
+			return nil, "", false, err
+		}
+		return wallet, privateKeyHex, false, wallet.Register(ctx, name)
+	}
+
+	wallet, err := upholdWalletFromPrivateKeyHex(previous.PrivateKey)
+	if err != nil {
+		return nil, "", false, err
+	}
+	resumed, err := wallet.ResumeRegistration(ctx)
+	if err != nil || resumed {
+		return wallet, previous.PrivateKey, resumed, err
+	}
+	return wallet, previous.PrivateKey, false, wallet.Register(ctx, name)
+}
+
+// readBatchNames reads the unique, non empty wallet names from the file at path
+func readBatchNames(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		names []string
+		seen  = map[string]bool{}
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		name := strings.TrimSpace(scanner.Text())
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names, scanner.Err()
+}
+
+// readBatchResults reads the results of a previous run, keyed by wallet name
+// a missing results file is treated as an empty one
+func readBatchResults(path string) (map[string]BatchResult, error) {
+	results := map[string]BatchResult{}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return results, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		var result BatchResult
+		if err := dec.Decode(&result); err != nil {
+			return nil, fmt.Errorf("failed to read results file %s: %w", path, err)
+		}
+		// keep successful registrations even if a later run recorded a failure for the same name
+		prev, ok := results[result.Name]
+		if ok && prev.ProviderID != "" {
+			continue
+		}
+		if ok && result.PrivateKey == "" {
+			// a run cancelled before trying again must not lose the key of an earlier attempt
+			result.PublicKey = prev.PublicKey
+			result.PrivateKey = prev.PrivateKey
+		}
+		results[result.Name] = result
+	}
+	return results, nil
+}
@@ -3,7 +3,8 @@ package cmd
 import (
 	"context"
 	"encoding/csv"
-	"flag"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"sort"
@@ -14,6 +15,7 @@ import (
 	"github.com/brave-intl/bat-go/libs/altcurrency"
 	"github.com/brave-intl/bat-go/libs/wallet"
 	"github.com/brave-intl/bat-go/libs/wallet/provider"
+	"github.com/shopspring/decimal"
 	"github.com/spf13/cobra"
 )
 
@@ -30,8 +32,9 @@ const (
 var (
 	// ListTransactionsCmd is a command to list transactions from a given wallet
 	ListTransactionsCmd = &cobra.Command{
-		Use:   "list-transactions",
+		Use:   "list-transactions PROVIDER_ID",
 		Short: "lists a transactions from a given wallet",
+		Args:  cobra.ExactArgs(1),
 		Run:   rootcmd.Perform("list transactions", RunListTransactions),
 	}
 )
@@ -42,9 +45,12 @@ func init() {
 	listTransactionsBuilder := cmdutils.NewFlagBuilder(ListTransactionsCmd)
 
 	listTransactionsBuilder.Flag().Bool("csv", false,
-		"the output file should be csv").
-		Bind("csv").
-		Require()
+		"the output file should be csv, cannot be combined with json").
+		Bind("csv")
+
+	listTransactionsBuilder.Flag().Bool("json", false,
+		"output the transactions as json, cannot be combined with csv").
+		Bind("json")
 
 	listTransactionsBuilder.Flag().Bool("signed", false,
 		"signed value depending on transaction direction").
@@ -52,7 +58,7 @@ func init() {
 		Require()
 
 	listTransactionsBuilder.Flag().Int("limit", 50,
-		"limit number of transactions returned").
+		"limit number of transactions returned, fetched a page at a time, 0 lists every transaction").
 		Bind("limit").
 		Require()
 
@@ -73,6 +79,13 @@ func RunListTransactions(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	jsonOut, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return err
+	}
+	if csvOut && jsonOut {
+		return errors.New("csv and json output cannot be combined")
+	}
 	signed, err := cmd.Flags().GetBool("signed")
 	if err != nil {
 		return err
@@ -93,6 +106,7 @@ func RunListTransactions(cmd *cobra.Command, args []string) error {
 		cmd.Context(),
 		args,
 		csvOut,
+		jsonOut,
 		signed,
 		limit,
 		startDateStr,
@@ -100,11 +114,62 @@ func RunListTransactions(cmd *cobra.Command, args []string) error {
 	)
 }
 
+// transactionJSON is the json output of a transaction, unlike wallet.TransactionInfo every field is included
+// amounts are encoded as decimal strings so no precision is lost
+type transactionJSON struct {
+	ID                 string                   `json:"id"`
+	Time               time.Time                `json:"time"`
+	Status             string                   `json:"status"`
+	Note               string                   `json:"note"`
+	Probi              decimal.Decimal          `json:"probi"`
+	AltCurrency        *altcurrency.AltCurrency `json:"altcurrency"`
+	Source             string                   `json:"source"`
+	Destination        string                   `json:"destination"`
+	TransferFee        decimal.Decimal          `json:"transferFee"`
+	ExchangeFee        decimal.Decimal          `json:"exchangeFee"`
+	DestAmount         decimal.Decimal          `json:"destAmount"`
+	DestCurrency       string                   `json:"destCurrency"`
+	ValidUntil         *time.Time               `json:"validUntil,omitempty"`
+	UserID             string                   `json:"userId,omitempty"`
+	KYC                bool                     `json:"kyc"`
+	CitizenshipCountry string                   `json:"citizenshipCountry,omitempty"`
+	IdentityCountry    string                   `json:"identityCountry,omitempty"`
+	ResidenceCountry   string                   `json:"residenceCountry,omitempty"`
+}
+
+func newTransactionJSON(t wallet.TransactionInfo) transactionJSON {
+	out := transactionJSON{
+		ID:                 t.ID,
+		Time:               t.Time,
+		Status:             t.Status,
+		Note:               t.Note,
+		Probi:              t.Probi,
+		AltCurrency:        t.AltCurrency,
+		Source:             t.Source,
+		Destination:        t.Destination,
+		TransferFee:        t.TransferFee,
+		ExchangeFee:        t.ExchangeFee,
+		DestAmount:         t.DestAmount,
+		DestCurrency:       t.DestCurrency,
+		UserID:             t.UserID,
+		KYC:                t.KYC,
+		CitizenshipCountry: t.CitizenshipCountry,
+		IdentityCountry:    t.IdentityCountry,
+		ResidenceCountry:   t.ResidenceCountry,
+	}
+	if !t.ValidUntil.IsZero() {
+		validUntil := t.ValidUntil
+		out.ValidUntil = &validUntil
+	}
+	return out
+}
+
 // ListTransactions lists transactions
 func ListTransactions(
 	ctx context.Context,
 	args []string,
 	csvOut bool,
+	jsonOut bool,
 	signed bool,
 	limit int,
 	startDateStr string,
@@ -122,7 +187,7 @@ func ListTransactions(
 	walletc := altcurrency.BAT
 	info := wallet.Info{
 		Provider:    walletProvider,
-		ProviderID:  flag.Args()[0],
+		ProviderID:  args[0],
 		AltCurrency: &walletc,
 	}
 	w, err := provider.GetWallet(ctx, info)
@@ -137,6 +202,16 @@ func ListTransactions(
 
 	sort.Sort(wallet.ByTime(txns))
 
+	if jsonOut {
+		out := make([]transactionJSON, 0, len(txns))
+		for _, t := range txns {
+			out = append(out, newTransactionJSON(t))
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
 	if csvOut {
 		w := csv.NewWriter(os.Stdout)
 		err = w.Write([]string{"id", "date", "description", "probi", "altcurrency", "source", "destination", "transferFee", "exchangeFee", "destAmount", "destCurrency"})
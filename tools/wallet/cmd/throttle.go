@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// forEachThrottled calls fn for each item from workers goroutines, starting at most one call per interval
+// so that a batch does not overwhelm the provider. Items which have not started when ctx is done are passed
+// to cancelled with the context error instead. It returns once every item has been handled
+func forEachThrottled(
+	ctx context.Context,
+	items []string,
+	workers int,
+	interval time.Duration,
+	fn func(item string),
+	cancelled func(item string, err error),
+) {
+	var (
+		wg       sync.WaitGroup
+		pending  = make(chan string)
+		throttle = time.NewTicker(interval)
+	)
+	defer throttle.Stop()
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range pending {
+				select {
+				case <-ctx.Done():
+					cancelled(item, ctx.Err())
+					continue
+				case <-throttle.C:
+				}
+				fn(item)
+			}
+		}()
+	}
+
+	for _, item := range items {
+		pending <- item
+	}
+	close(pending)
+	wg.Wait()
+}
@@ -4,15 +4,20 @@ import (
 	"bufio"
 	"context"
 	"crypto"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 
 	cmdutils "github.com/brave-intl/bat-go/cmd"
 	rootcmd "github.com/brave-intl/bat-go/cmd"
 	"github.com/brave-intl/bat-go/libs/altcurrency"
+	"github.com/brave-intl/bat-go/libs/clients/gemini"
 	appctx "github.com/brave-intl/bat-go/libs/context"
+	"github.com/brave-intl/bat-go/libs/cryptography"
+	"github.com/brave-intl/bat-go/libs/custodian"
 	"github.com/brave-intl/bat-go/libs/httpsignature"
 	"github.com/brave-intl/bat-go/libs/logging"
 	"github.com/brave-intl/bat-go/libs/passphrase"
@@ -20,6 +25,7 @@ import (
 	"github.com/brave-intl/bat-go/libs/wallet"
 	"github.com/brave-intl/bat-go/libs/wallet/provider/uphold"
 	vaultsigner "github.com/brave-intl/bat-go/tools/vault/signer"
+	uuid "github.com/satori/go.uuid"
 	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -86,7 +92,7 @@ func init() {
 		Require()
 
 	transferFundsBuilder.Flag().String("provider", "uphold",
-		"provider for the source wallet").
+		"provider for the source wallet [uphold or gemini]").
 		Bind("provider")
 
 	transferFundsBuilder.Flag().Bool("usevault", false,
@@ -140,10 +146,15 @@ func RunTransferFunds(command *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	provider, err := command.Flags().GetString("provider")
+	if err != nil {
+		return err
+	}
 
 	ctx := command.Context()
 	return TransferFunds(
 		ctx,
+		provider,
 		from,
 		to,
 		value,
@@ -230,8 +241,106 @@ func pullRequisiteSecretsFromVault(from string) (string, *vaultsigner.Ed25519Sig
 	return providerIDString, signer, nil
 }
 
-// TransferFunds transfers funds to a wallet
+func pullGeminiSecrets(from string, usevault bool) (string, string, cryptography.HMACKey, error) {
+	if !usevault {
+		return os.Getenv("GEMINI_CLIENT_ID"),
+			os.Getenv("GEMINI_API_KEY"),
+			cryptography.NewHMACHasher([]byte(os.Getenv("GEMINI_API_SECRET"))),
+			nil
+	}
+
+	wrappedClient, err := vaultsigner.Connect()
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	response, err := wrappedClient.Client.Logical().Read("wallets/" + from)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if response == nil {
+		return "", "", nil, errors.New("invalid wallet name")
+	}
+
+	clientID, ok := response.Data["clientid"].(string)
+	if !ok {
+		return "", "", nil, errors.New("wallet is missing a gemini client id")
+	}
+	clientKey, ok := response.Data["clientkey"].(string)
+	if !ok {
+		return "", "", nil, errors.New("wallet is missing a gemini client key")
+	}
+
+	signer, err := wrappedClient.GetHmacSecret(from)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return clientID, clientKey, signer, nil
+}
+
+// resolveTransferAmount converts the requested value into the amount to transfer, either the whole spendable
+// balance for "all" or the nominal value, which must not exceed the spendable balance. spendableProbi is nil
+// when the balance is held in a different currency and cannot be checked
+func resolveTransferAmount(
+	value string,
+	altc altcurrency.AltCurrency,
+	spendableProbi *decimal.Decimal,
+) (altcurrency.Money, error) {
+	if value == "all" {
+		if spendableProbi == nil {
+			return altcurrency.Money{}, errors.New("sending all funds not available for currencies other than the wallet currency")
+		}
+		return altcurrency.NewProbi(altc, *spendableProbi), nil
+	}
+
+	valueDec, err := decimal.NewFromString(value)
+	if err != nil || valueDec.LessThanOrEqual(decimal.Zero) {
+		return altcurrency.Money{}, errors.New("must pass --value greater than 0 or --value=all")
+	}
+	amount := altcurrency.NewNominal(altc, valueDec).ToProbi()
+	if spendableProbi != nil {
+		cmp, err := amount.Cmp(altcurrency.NewProbi(altc, *spendableProbi))
+		if err != nil {
+			return altcurrency.Money{}, err
+		}
+		if cmp > 0 {
+			return altcurrency.Money{}, errors.New("insufficient funds in wallet")
+		}
+	}
+	return amount, nil
+}
+
+// TransferFunds transfers funds to a wallet using the source wallet's provider
 func TransferFunds(
+	ctx context.Context,
+	provider string,
+	from string,
+	to string,
+	value string,
+	currency string,
+	note string,
+	purpose string,
+	beneficiary *uphold.Beneficiary,
+	oneshot bool,
+	usevault bool,
+) error {
+	valueDec, err := decimal.NewFromString(value)
+	if value != "all" && (err != nil || valueDec.LessThanOrEqual(decimal.Zero)) {
+		return errors.New("must pass --value greater than 0 or --value=all")
+	}
+
+	switch provider {
+	case "uphold":
+		return transferUpholdFunds(ctx, from, to, value, currency, note, purpose, beneficiary, oneshot, usevault)
+	case "gemini":
+		return transferGeminiFunds(ctx, from, to, value, currency, oneshot, usevault)
+	default:
+		return fmt.Errorf("unsupported wallet provider %q", provider)
+	}
+}
+
+// transferUpholdFunds transfers funds from an uphold card, confirming the transaction once it is prepared
+func transferUpholdFunds(
 	ctx context.Context,
 	from string,
 	to string,
@@ -248,10 +357,6 @@ func TransferFunds(
 		_, logger = logging.SetupLogger(ctx)
 	}
 	logger.Debug().Msg("debug enabled")
-	valueDec, err := decimal.NewFromString(value)
-	if value != "all" && (err != nil || valueDec.LessThanOrEqual(decimal.Zero)) {
-		return errors.New("must pass --value greater than 0 or --value=all")
-	}
 
 	providerID, signer, err := pullRequisiteSecrets(from, usevault)
 	if err != nil {
@@ -284,30 +389,21 @@ func TransferFunds(
 		return err
 	}
 
-	var valueProbi decimal.Decimal
-	var balance *wallet.Balance
-
+	var spendableProbi *decimal.Decimal
 	if walletc == altc {
-		balance, err = w.GetBalance(ctx, true)
+		balance, err := w.GetBalance(ctx, true)
 		if err != nil {
 			return err
 		}
+		spendableProbi = &balance.SpendableProbi
 	}
 
-	if value == "all" {
-		if walletc == altc {
-			valueProbi = balance.SpendableProbi
-		} else {
-			return errors.New("sending all funds not available for currencies other than the wallet currency")
-		}
-	} else {
-		valueProbi = altc.ToProbi(valueDec)
-		if walletc == altc && balance.SpendableProbi.LessThan(valueProbi) {
-			return errors.New("insufficient funds in wallet")
-		}
+	amount, err := resolveTransferAmount(value, altc, spendableProbi)
+	if err != nil {
+		return err
 	}
 
-	signedTx, err := w.PrepareTransaction(altc, valueProbi, to, note, purpose, beneficiary)
+	signedTx, err := w.PrepareTransaction(altc, amount.Probi(), to, note, purpose, beneficiary)
 	if err != nil {
 		return err
 	}
@@ -326,7 +422,7 @@ func TransferFunds(
 			Str("from", from).
 			Str("to", to).
 			Str("currency", currency).
-			Str("amount", altc.FromProbi(valueProbi).String()).
+			Str("amount", amount.Nominal().String()).
 			Msg("will transfer")
 
 		log.Printf("Continue? ")
@@ -364,3 +460,140 @@ func TransferFunds(
 	}
 	return nil
 }
+
+// transferGeminiFunds transfers funds from a gemini account as a single payout, gemini has no separate
+// confirmation step so the transfer is confirmed before it is submitted
+func transferGeminiFunds(
+	ctx context.Context,
+	from string,
+	to string,
+	value string,
+	currency string,
+	oneshot bool,
+	usevault bool,
+) error {
+	logger, err := appctx.GetLogger(ctx)
+	if err != nil {
+		_, logger = logging.SetupLogger(ctx)
+	}
+
+	clientID, apiKey, signer, err := pullGeminiSecrets(from, usevault)
+	if err != nil {
+		return err
+	}
+	if clientID == "" || apiKey == "" {
+		return errors.New("a gemini client id and api key are required (GEMINI_CLIENT_ID, GEMINI_API_KEY)")
+	}
+
+	client, err := gemini.New()
+	if err != nil {
+		return err
+	}
+
+	altc, err := altcurrency.FromString(currency)
+	if err != nil {
+		return err
+	}
+
+	balancesPayload, err := json.Marshal(gemini.NewBalancesPayload(nil))
+	if err != nil {
+		return err
+	}
+	balances, err := client.FetchBalances(ctx, apiKey, signer, string(balancesPayload))
+	if err != nil {
+		return err
+	}
+	spendableProbi := decimal.Zero
+	for _, balance := range *balances {
+		if balance.Currency == altc.String() {
+			spendableProbi = altc.ToProbi(balance.Available)
+		}
+	}
+
+	amount, err := resolveTransferAmount(value, altc, &spendableProbi)
+	if err != nil {
+		return err
+	}
+
+	// the tx ref is reused if the upload is retried so gemini does not pay out twice
+	payout := gemini.PayoutPayload{
+		TxRef: gemini.GenerateTxRef(&custodian.Transaction{
+			SettlementID: uuid.NewV4().String(),
+			Type:         "transfer",
+			Destination:  to,
+			Channel:      from,
+		}),
+		Amount:      amount.Nominal(),
+		Currency:    altc.String(),
+		Destination: to,
+	}
+
+	for {
+		if !oneshot {
+			logger.Info().
+				Str("tx_ref", payout.TxRef).
+				Str("from", from).
+				Str("to", to).
+				Str("currency", currency).
+				Str("amount", amount.Nominal().String()).
+				Msg("will transfer")
+
+			log.Printf("Continue? ")
+			resp, err := prompt.Bool()
+			if err != nil {
+				return err
+			}
+			if !resp {
+				return errors.New("exiting")
+			}
+		}
+
+		payload, err := json.Marshal(gemini.NewBulkPayoutPayload(nil, clientID, &[]gemini.PayoutPayload{payout}))
+		if err != nil {
+			return err
+		}
+		results, err := client.UploadBulkPayout(ctx, apiKey, signer, base64.StdEncoding.EncodeToString(payload))
+		if err != nil {
+			logger.Error().Err(err).Msg("error submitting")
+			return err
+		}
+		if results == nil || len(*results) != 1 {
+			return errors.New("unexpected response submitting transfer")
+		}
+
+		result := (*results)[0]
+		if result.Result == "OK" {
+			status := ""
+			if result.Status != nil {
+				status = *result.Status
+			}
+			logger.Info().
+				Str("tx_ref", result.TxRef).
+				Str("status", status).
+				Msg("transfer complete")
+			break
+		}
+
+		reason := ""
+		if result.Reason != nil {
+			reason = *result.Reason
+		}
+		logger.Error().
+			Str("tx_ref", result.TxRef).
+			Str("reason", reason).
+			Msg("transfer failed")
+		if oneshot {
+			return fmt.Errorf("transfer failed: %s", reason)
+		}
+
+		log.Printf("Transfer did not appear to go through, retry?")
+		resp, err := prompt.Bool()
+		if err != nil {
+			return err
+		}
+		if !resp {
+			return errors.New("exiting")
+		}
+	}
+	return nil
+}